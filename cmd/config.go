@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var errFailedParseConfig = errors.New("failed to parse config file")
+
+// config represents the contents of the config file, e.g.
+//
+//	{
+//	  "resources": {
+//	    "products": { "id": "sku", "idGenerator": "uuid" },
+//	    "books": { "indexes": ["author", "published"] },
+//	    "comments": { "parents": ["posts"] }
+//	  }
+//	}
+type config struct {
+	// Resources contains settings per resource key, which override the ones of flags.
+	Resources map[string]resourceConfig `json:"resources"`
+}
+
+// resourceConfig represents the settings of a single resource.
+type resourceConfig struct {
+	// ID is the name of the id field.
+	ID string `json:"id,omitempty"`
+	// IDGenerator is the generator of ids for new resources.
+	IDGenerator string `json:"idGenerator,omitempty"`
+	// Indexes are the fields that resources are indexed by, for the memory storage engine.
+	Indexes []string `json:"indexes,omitempty"`
+	// Parents are the resources that resources refer to by foreign key, which nested routes are registered
+	// under, e.g. 'posts' of 'comments'. Defaults to every other resource.
+	Parents []string `json:"parents,omitempty"`
+}
+
+// readConfig returns the settings of the config file. An empty filename results in empty settings.
+func readConfig(filename string) (*config, error) {
+	cfg := &config{Resources: make(map[string]resourceConfig)}
+	if filename == "" {
+		return cfg, nil
+	}
+
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errFileNotFound, filename)
+	}
+
+	if err = json.Unmarshal(contentBytes, cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s", errFailedParseConfig, filename)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	// importCmd represents the import command.
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import the resources of a data file to a database",
+		Long: `
+Replaces the contents of the database with the resources of the data file, 
+so they can be served with 'json-server start --db'.`,
+		RunE: runImport,
+	}
+
+	// Optional flag to set the data file to import, in json, json5, yaml, toml or csv format.
+	importCmd.Flags().StringP("file", "f", "db.json", "File to import")
+	// Required flag to set the database url.
+	importCmd.Flags().String("db", "", "Database url to import to, e.g. sqlite://data.db or bolt://data.db")
+	_ = importCmd.MarkFlagRequired("db")
+
+	return importCmd
+}
+
+func runImport(cmd *cobra.Command, _ []string) error {
+	// Parse command's flags.
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("%w: file", errFailedParseFlag)
+	}
+
+	dbURL, err := cmd.Flags().GetString("db")
+	if err != nil {
+		return fmt.Errorf("%w: db", errFailedParseFlag)
+	}
+
+	storageEngine, filename, err := parseDatabaseURL(dbURL)
+	if err != nil {
+		return err
+	}
+
+	// Validate the resources of file, before replacing the contents of the database.
+	if _, _, err = getSourceKeys(file); err != nil {
+		return err
+	}
+
+	engine, err := newDatabaseStorageEngine(storageEngine, filename)
+	if err != nil {
+		return err
+	}
+	defer engine.close()
+
+	if err = engine.importFile(file); err != nil {
+		return fmt.Errorf("%w: %s", errFailedParseFile, file)
+	}
+
+	fmt.Printf("Imported resources of %s to %s\n", file, dbURL)
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chanioxaris/json-server/internal/generator"
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+var (
+	errFileExists        = errors.New("file already exists, use --force to overwrite")
+	errUnsupportedFormat = errors.New("unsupported file format")
+	errUnknownTemplate   = errors.New("unknown template")
+	errInvalidCount      = errors.New("invalid count of resources")
+	errFailedCreateFile  = errors.New("failed to create file")
+)
+
+// initTemplates contains the resources of the templates of the init command.
+var initTemplates = map[string][]string{
+	"blog": {"posts", "comments", "profile"},
+	"shop": {"products", "customers", "orders"},
+	"todo": {"users", "todos"},
+}
+
+// sampleFields contains the field templates of sample resources, by resource key. Other keys get
+// generic fields, while count is used for the ids of related resources.
+var sampleFields = map[string]func(count int) map[string]interface{}{
+	"users": func(int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "name": "{{name}}", "username": "{{username}}", "email": "{{email}}", "phone": "{{phone}}"}
+	},
+	"posts": func(int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "title": "{{sentence 3 6}}", "body": "{{paragraph 1 2}}", "author": "{{name}}", "published": "{{date 2020 2026}}"}
+	},
+	"comments": func(count int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "postId": relatedID(count), "name": "{{name}}", "email": "{{email}}", "body": "{{sentence}}"}
+	},
+	"todos": func(count int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "userId": relatedID(count), "title": "{{sentence 3 6}}", "completed": "{{bool}}"}
+	},
+	"albums": func(count int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "userId": relatedID(count), "title": "{{sentence 2 4}}"}
+	},
+	"photos": func(count int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "albumId": relatedID(count), "title": "{{sentence 2 4}}", "url": "https://picsum.photos/seed/{{seq}}/600"}
+	},
+	"products": func(int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "name": "{{word}} {{word}}", "price": "{{float 1 100}}", "stock": "{{int 0 500}}", "category": `{{pick "books" "games" "music" "tools"}}`}
+	},
+	"customers": func(int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "name": "{{name}}", "email": "{{email}}", "city": "{{city}}", "country": "{{country}}"}
+	},
+	"orders": func(count int) map[string]interface{} {
+		return map[string]interface{}{"id": "{{seq}}", "customerId": relatedID(count), "productId": relatedID(count), "quantity": "{{int 1 5}}", "status": `{{pick "pending" "shipped" "delivered"}}`, "createdAt": "{{date 2020 2026}}"}
+	},
+}
+
+// singularFields contains the field templates of sample singular resources, by resource key.
+var singularFields = map[string]map[string]interface{}{
+	"profile":  {"name": "{{name}}", "email": "{{email}}", "company": "{{company}}"},
+	"settings": {"theme": `{{pick "dark" "light"}}`, "language": `{{pick "en" "el" "de"}}`},
+}
+
+func newInitCmd() *cobra.Command {
+	// initCmd represents the init command.
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a starter data file and config file",
+		Long: `
+Creates a data file with sample resources, along with a config file with their settings,
+so they can be served with 'json-server start'. Resources are selected from a template,
+from flags, or interactively when neither is provided. Existing files are never
+overwritten, unless forced.`,
+		RunE: runInit,
+	}
+
+	// Optional flag to set the data file, in json, json5, yaml or toml format.
+	initCmd.Flags().StringP("file", "f", "db.json", "Data file to create, in json, json5, yaml or toml format")
+	// Optional flag to set the config file, which is skipped if empty.
+	initCmd.Flags().StringP("config", "c", "json-server.json", "Config file to create, none if empty")
+	// Optional flags to set the resources to create.
+	initCmd.Flags().String("template", "blog", "Template of resources, one of 'blog', 'shop' or 'todo'")
+	initCmd.Flags().StringSlice("resources", nil, "Resources to create, e.g. users,posts, which take precedence over the template")
+	initCmd.Flags().Int("count", 10, "Number of sample records of every resource")
+	// Optional flag to skip prompts, using the values of flags.
+	initCmd.Flags().BoolP("yes", "y", false, "Don't prompt for resources, use the values of flags")
+	// Optional flag to overwrite existing files.
+	initCmd.Flags().Bool("force", false, "Overwrite existing files")
+
+	return initCmd
+}
+
+func runInit(cmd *cobra.Command, _ []string) error {
+	// Parse command's flags.
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("%w: file", errFailedParseFlag)
+	}
+
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("%w: config", errFailedParseFlag)
+	}
+
+	template, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return fmt.Errorf("%w: template", errFailedParseFlag)
+	}
+
+	resources, err := cmd.Flags().GetStringSlice("resources")
+	if err != nil {
+		return fmt.Errorf("%w: resources", errFailedParseFlag)
+	}
+
+	count, err := cmd.Flags().GetInt("count")
+	if err != nil {
+		return fmt.Errorf("%w: count", errFailedParseFlag)
+	}
+
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return fmt.Errorf("%w: yes", errFailedParseFlag)
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return fmt.Errorf("%w: force", errFailedParseFlag)
+	}
+
+	// Files of formats that contain a single resource, e.g. csv, can't contain the starter resources.
+	if !storage.SupportedFile(file) || storage.FileKey(file) != "" || isGeneratorFile(file) {
+		return fmt.Errorf("%w: %s", errUnsupportedFormat, file)
+	}
+
+	// Refuse to overwrite any existing file, before prompting for or creating any of them.
+	for _, filename := range []string{file, configFile} {
+		if filename == "" || force {
+			continue
+		}
+
+		if _, err = os.Stat(filename); err == nil {
+			return fmt.Errorf("%w: %s", errFileExists, filename)
+		}
+	}
+
+	if len(resources) == 0 {
+		templateResources, ok := initTemplates[template]
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownTemplate, template)
+		}
+
+		resources = templateResources
+	}
+
+	// Prompt for resources, unless they are provided by flags.
+	interactive := !yes && isTerminal(os.Stdin) &&
+		!cmd.Flags().Changed("template") && !cmd.Flags().Changed("resources") && !cmd.Flags().Changed("count")
+	if interactive {
+		if resources, count, err = promptResources(cmd.InOrStdin(), resources, count); err != nil {
+			return err
+		}
+	}
+
+	if count < 0 {
+		return fmt.Errorf("%w: %d", errInvalidCount, count)
+	}
+
+	content, err := generator.New(time.Now().UnixNano()).Generate(sampleSpec(resources, count))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errFailedGenerate, err)
+	}
+
+	if err = storage.WriteContent(file, "", content); err != nil {
+		return fmt.Errorf("%w: %s", errFailedCreateFile, file)
+	}
+
+	fmt.Printf("Created %s with resources %s\n", file, strings.Join(resources, ", "))
+
+	if configFile == "" {
+		return nil
+	}
+
+	if err = writeConfig(configFile, sampleConfig(resources, count)); err != nil {
+		return fmt.Errorf("%w: %s", errFailedCreateFile, configFile)
+	}
+
+	fmt.Printf("Created %s\n\n", configFile)
+	fmt.Printf("Run 'json-server start -f %s -c %s' to serve them\n", file, configFile)
+
+	return nil
+}
+
+// promptResources prompts for the resources and the number of sample records of every resource,
+// using the provided values as defaults for empty answers.
+func promptResources(in io.Reader, resources []string, count int) ([]string, int, error) {
+	reader := bufio.NewReader(in)
+
+	fmt.Printf("Resources (comma separated) [%s]: ", strings.Join(resources, ","))
+	answer, err := readAnswer(reader)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if answer != "" {
+		resources = make([]string, 0)
+		for _, resource := range strings.Split(answer, ",") {
+			if resource = strings.TrimSpace(resource); resource != "" {
+				resources = append(resources, resource)
+			}
+		}
+	}
+
+	fmt.Printf("Sample records of every resource [%d]: ", count)
+	if answer, err = readAnswer(reader); err != nil {
+		return nil, 0, err
+	}
+
+	if answer != "" {
+		if count, err = strconv.Atoi(answer); err != nil {
+			return nil, 0, fmt.Errorf("%w: %s", errInvalidCount, answer)
+		}
+	}
+
+	return resources, count, nil
+}
+
+// readAnswer returns the next line of input, without surrounding whitespace.
+func readAnswer(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// isTerminal reports whether the file is a terminal, e.g. to prompt for input.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// sampleSpec returns the generator spec of the sample records of the resources.
+func sampleSpec(resources []string, count int) map[string]interface{} {
+	spec := make(map[string]interface{}, len(resources))
+	for _, resource := range resources {
+		if fields, ok := singularFields[resource]; ok {
+			spec[resource] = map[string]interface{}{"fields": fields}
+			continue
+		}
+
+		spec[resource] = map[string]interface{}{"count": float64(count), "fields": resourceFields(resource, count)}
+	}
+
+	return spec
+}
+
+// sampleConfig returns the settings of the resources, which are indexed by the ids of their related resources.
+func sampleConfig(resources []string, count int) *config {
+	cfg := &config{Resources: make(map[string]resourceConfig)}
+	for _, resource := range resources {
+		if _, ok := singularFields[resource]; ok {
+			continue
+		}
+
+		indexes := make([]string, 0)
+		for field := range resourceFields(resource, count) {
+			if strings.HasSuffix(field, "Id") {
+				indexes = append(indexes, field)
+			}
+		}
+
+		sort.Strings(indexes)
+
+		cfg.Resources[resource] = resourceConfig{
+			ID:          "id",
+			IDGenerator: storage.IDGeneratorAutoIncrement,
+			Indexes:     indexes,
+		}
+	}
+
+	return cfg
+}
+
+// resourceFields returns the field templates of the sample records of the resource.
+func resourceFields(resource string, count int) map[string]interface{} {
+	if fields, ok := sampleFields[resource]; ok {
+		return fields(count)
+	}
+
+	return map[string]interface{}{"id": "{{seq}}", "name": "{{word}} {{word}}", "description": "{{sentence}}", "createdAt": "{{date 2020 2026}}"}
+}
+
+// relatedID returns the template of the id of a related resource, among count resources.
+func relatedID(count int) string {
+	if count < 1 {
+		count = 1
+	}
+
+	return fmt.Sprintf("{{int 1 %d}}", count)
+}
+
+// writeConfig writes the settings to the config file.
+func writeConfig(filename string, cfg *config) error {
+	contentBytes, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, append(contentBytes, '\n'), 0644)
+}
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestSampleSpec(t *testing.T) {
+	testCases := []struct {
+		name      string
+		resources []string
+		count     int
+		expected  map[string]interface{}
+	}{
+		{
+			name:      "Known, singular and generic resources",
+			resources: []string{"todos", "settings", "widgets"},
+			count:     5,
+			expected: map[string]interface{}{
+				"todos": map[string]interface{}{
+					"count": float64(5),
+					"fields": map[string]interface{}{
+						"id":        "{{seq}}",
+						"userId":    "{{int 1 5}}",
+						"title":     "{{sentence 3 6}}",
+						"completed": "{{bool}}",
+					},
+				},
+				"settings": map[string]interface{}{
+					"fields": map[string]interface{}{
+						"theme":    `{{pick "dark" "light"}}`,
+						"language": `{{pick "en" "el" "de"}}`,
+					},
+				},
+				"widgets": map[string]interface{}{
+					"count": float64(5),
+					"fields": map[string]interface{}{
+						"id":          "{{seq}}",
+						"name":        "{{word}} {{word}}",
+						"description": "{{sentence}}",
+						"createdAt":   "{{date 2020 2026}}",
+					},
+				},
+			},
+		},
+		{
+			name:      "No records",
+			resources: []string{"albums"},
+			count:     0,
+			expected: map[string]interface{}{
+				"albums": map[string]interface{}{
+					"count": float64(0),
+					"fields": map[string]interface{}{
+						"id":     "{{seq}}",
+						"userId": "{{int 1 1}}",
+						"title":  "{{sentence 2 4}}",
+					},
+				},
+			},
+		},
+		{
+			name:      "No resources",
+			resources: []string{},
+			count:     10,
+			expected:  map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sampleSpec(tc.resources, tc.count)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected spec %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSampleConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		resources []string
+		expected  *config
+	}{
+		{
+			name:      "Indexes of related resources",
+			resources: []string{"orders", "products", "profile"},
+			expected: &config{Resources: map[string]resourceConfig{
+				"orders": {
+					ID:          "id",
+					IDGenerator: storage.IDGeneratorAutoIncrement,
+					Indexes:     []string{"customerId", "productId"},
+				},
+				"products": {
+					ID:          "id",
+					IDGenerator: storage.IDGeneratorAutoIncrement,
+					Indexes:     []string{},
+				},
+			}},
+		},
+		{
+			name:      "Only singular resources",
+			resources: []string{"profile", "settings"},
+			expected:  &config{Resources: map[string]resourceConfig{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sampleConfig(tc.resources, 10)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected config %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPromptResources(t *testing.T) {
+	testCases := []struct {
+		name              string
+		input             string
+		expectedResources []string
+		expectedCount     int
+		err               error
+	}{
+		{
+			name:              "Defaults on empty answers",
+			input:             "\n\n",
+			expectedResources: []string{"posts", "comments"},
+			expectedCount:     10,
+		},
+		{
+			name:              "Defaults on end of input",
+			input:             "",
+			expectedResources: []string{"posts", "comments"},
+			expectedCount:     10,
+		},
+		{
+			name:              "Answered resources and count",
+			input:             " users, todos,,\n20\n",
+			expectedResources: []string{"users", "todos"},
+			expectedCount:     20,
+		},
+		{
+			name:              "Answers without trailing newline",
+			input:             "users\n5",
+			expectedResources: []string{"users"},
+			expectedCount:     5,
+		},
+		{
+			name:  "Invalid count",
+			input: "\nmany\n",
+			err:   errInvalidCount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources, count, err := promptResources(strings.NewReader(tc.input), []string{"posts", "comments"}, 10)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, but got %v", tc.err, err)
+			}
+
+			if tc.err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(resources, tc.expectedResources) {
+				t.Fatalf("expected resources %v, but got %v", tc.expectedResources, resources)
+			}
+
+			if count != tc.expectedCount {
+				t.Fatalf("expected count %v, but got %v", tc.expectedCount, count)
+			}
+		})
+	}
+}
+
+func TestRunInit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		file     string
+		existing []string
+		args     []string
+		err      error
+	}{
+		{
+			name: "Create data and config file",
+			file: "db.json",
+		},
+		{
+			name: "Create yaml data file",
+			file: "db.yaml",
+			args: []string{"--resources", "users,todos"},
+		},
+		{
+			name: "Create json5 data file",
+			file: "db.json5",
+			args: []string{"--resources", "users,todos"},
+		},
+		{
+			name:     "Existing data file",
+			file:     "db.json",
+			existing: []string{"db.json"},
+			err:      errFileExists,
+		},
+		{
+			name:     "Existing config file",
+			file:     "db.json",
+			existing: []string{"json-server.json"},
+			err:      errFileExists,
+		},
+		{
+			name:     "Overwrite existing files when forced",
+			file:     "db.json",
+			existing: []string{"db.json", "json-server.json"},
+			args:     []string{"--force"},
+		},
+		{
+			name: "Csv data file",
+			file: "posts.csv",
+			err:  errUnsupportedFormat,
+		},
+		{
+			name: "Generator data file",
+			file: "seed.gen.json",
+			err:  errUnsupportedFormat,
+		},
+		{
+			name: "Unsupported data file",
+			file: "db.xml",
+			err:  errUnsupportedFormat,
+		},
+		{
+			name: "Unknown template",
+			file: "db.json",
+			args: []string{"--template", "forum"},
+			err:  errUnknownTemplate,
+		},
+		{
+			name: "Negative count",
+			file: "db.json",
+			args: []string{"--count", "-1"},
+			err:  errInvalidCount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			for _, existing := range tc.existing {
+				if err = ioutil.WriteFile(filepath.Join(dir, existing), []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			file := filepath.Join(dir, tc.file)
+			configFile := filepath.Join(dir, "json-server.json")
+
+			initCmd := newInitCmd()
+			initCmd.SetArgs(append([]string{"--yes", "-f", file, "-c", configFile}, tc.args...))
+			initCmd.SilenceUsage = true
+			initCmd.SilenceErrors = true
+
+			if err = initCmd.Execute(); !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, but got %v", tc.err, err)
+			}
+
+			if tc.err != nil {
+				// Existing files are kept as is, while no other files are created.
+				for _, filename := range []string{file, configFile} {
+					contentBytes, err := ioutil.ReadFile(filename)
+					if os.IsNotExist(err) {
+						continue
+					}
+
+					if got := string(contentBytes); got != "{}" {
+						t.Fatalf("expected content of %s %q, but got %q", filename, "{}", got)
+					}
+				}
+
+				return
+			}
+
+			content, err := storage.ReadContent(file, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := content.(map[string]interface{}); !ok {
+				t.Fatalf("expected content of %s to be an object, but got %T", file, content)
+			}
+
+			cfg, err := readConfig(configFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(cfg.Resources) == 0 {
+				t.Fatalf("expected settings of resources in %s, but got none", configFile)
+			}
+
+			// Created files can be served and changed, whatever their format.
+			key := sortedResourceKeys(cfg)[0]
+
+			storageSvc, err := storage.NewFile(file, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = storageSvc.Create(storage.Resource{"name": "new"}); err != nil {
+				t.Fatalf("expected to create a resource of %s in %s, but got %v", key, file, err)
+			}
+		})
+	}
+}
+
+// sortedResourceKeys returns the keys of the settings of resources, in alphabetical order.
+func sortedResourceKeys(cfg *config) []string {
+	keys := make([]string, 0, len(cfg.Resources))
+	for key := range cfg.Resources {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -23,7 +23,10 @@ func newRootCmd() *cobra.Command {
 	}
 
 	// Add sub commands to base command.
+	rootCmd.AddCommand(newInitCmd())
 	rootCmd.AddCommand(newStartCmd())
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newValidateCmd())
 	rootCmd.AddCommand(newVersionCmd())
 
 	return rootCmd
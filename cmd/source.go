@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chanioxaris/json-server/internal/generator"
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+var (
+	errDuplicateResource = errors.New("resource defined in multiple files")
+	errFailedGenerate    = errors.New("failed to generate resources")
+)
+
+// source is a data file of resources, in any of the supported formats. It contains either the resources
+// of every key, or only the ones of a single key named after the file, e.g. 'books.json' of a directory.
+type source struct {
+	filename string
+	// key is the key of the only resources of the file, if it doesn't contain the ones of every key.
+	key string
+}
+
+// expandSources returns the sources of the provided paths. Files contain the resources of every key,
+// unless their format only supports the ones of a single key, e.g. '*.csv', while every file of a
+// supported format in a directory, e.g. '*.json' or '*.yaml', contains the resources of a single key.
+func expandSources(paths []string) ([]source, error) {
+	sources := make([]source, 0, len(paths))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errFileNotFound, path)
+		}
+
+		if !info.IsDir() {
+			sources = append(sources, source{filename: path, key: storage.FileKey(path)})
+			continue
+		}
+
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errFileNotFound, path)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !storage.SupportedFile(entry.Name()) {
+				continue
+			}
+
+			filename := filepath.Join(path, entry.Name())
+
+			// Generator files contain the templates of every key.
+			if isGeneratorFile(filename) {
+				sources = append(sources, source{filename: filename})
+				continue
+			}
+
+			key := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			sources = append(sources, source{filename: filename, key: key})
+		}
+	}
+
+	return sources, nil
+}
+
+// sourceKeys returns the keys of resources and singular resources of the sources, along with the
+// source of every key. Keys of generator files are the ones of their generated documents.
+func sourceKeys(sources []source, generated map[source]*storage.MemoryDocument) ([]string, []string, map[string]source, error) {
+	resourceKeys := make([]string, 0)
+	singularKeys := make([]string, 0)
+	routes := make(map[string]source)
+
+	for _, src := range sources {
+		var (
+			keys, singular []string
+			err            error
+		)
+
+		if doc, ok := generated[src]; ok {
+			keys, singular = doc.Keys()
+		} else if src.key == "" {
+			keys, singular, err = getResourceKeys(src.filename)
+		} else {
+			keys, singular, err = getFileResourceKeys(src.filename, src.key)
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		for _, key := range append(append([]string{}, keys...), singular...) {
+			if other, ok := routes[key]; ok {
+				return nil, nil, nil, fmt.Errorf("%w: %s in %s and %s", errDuplicateResource, key, other.filename, src.filename)
+			}
+
+			routes[key] = src
+		}
+
+		resourceKeys = append(resourceKeys, keys...)
+		singularKeys = append(singularKeys, singular...)
+	}
+
+	sort.Strings(resourceKeys)
+	sort.Strings(singularKeys)
+
+	return resourceKeys, singularKeys, routes, nil
+}
+
+// getSourceKeys returns the keys of resources and singular resources of a file, which contains either
+// the resources of every key, or only the ones of a single key by its format.
+func getSourceKeys(filename string) ([]string, []string, error) {
+	if key := storage.FileKey(filename); key != "" {
+		return getFileResourceKeys(filename, key)
+	}
+
+	return getResourceKeys(filename)
+}
+
+// getFileResourceKeys returns the key of a file, which contains only the resources of that key, either
+// as a resource or as a singular resource.
+func getFileResourceKeys(filename, key string) ([]string, []string, error) {
+	data, err := readContent(filename, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch data.(type) {
+	case []interface{}:
+		return []string{key}, []string{}, nil
+	case map[string]interface{}:
+		return []string{}, []string{key}, nil
+	default:
+		return nil, nil, errUnsupportedResource
+	}
+}
+
+// readContent returns the contents of the file, decoded by the format of its extension. If key is not
+// empty, the file contains only the value of that key.
+func readContent(filename, key string) (interface{}, error) {
+	content, err := storage.ReadContent(filename, key)
+
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return nil, fmt.Errorf("%w: %s", errFileNotFound, filename)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errFailedParseFile, filename)
+	}
+
+	return content, nil
+}
+
+// readSources returns the contents of the sources by source, except for generator files, which contain
+// templates of resources.
+func readSources(sources []source) (map[source]interface{}, error) {
+	contents := make(map[source]interface{}, len(sources))
+	for _, src := range sources {
+		if isGeneratorFile(src.filename) {
+			continue
+		}
+
+		content, err := readContent(src.filename, src.key)
+		if err != nil {
+			return nil, err
+		}
+
+		contents[src] = content
+	}
+
+	return contents, nil
+}
+
+// isGeneratorFile reports whether the file contains templates of resources to generate, instead of
+// resources, e.g. 'seed.gen.yaml'.
+func isGeneratorFile(filename string) bool {
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+
+	return storage.SupportedFile(filename) && strings.HasSuffix(base, ".gen")
+}
+
+// generateContent returns the resources generated from the templates of the generator file, with
+// the seed.
+func generateContent(filename string, seed int64) (map[string]interface{}, error) {
+	value, err := readContent(filename, "")
+	if err != nil {
+		return nil, err
+	}
+
+	spec, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errFailedParseFile, filename)
+	}
+
+	content, err := generator.New(seed).Generate(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", errFailedGenerate, filename, err)
+	}
+
+	return content, nil
+}
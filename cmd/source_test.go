@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestExpandSources(t *testing.T) {
+	testCases := []struct {
+		name     string
+		paths    []string
+		expected []source
+		err      error
+	}{
+		{
+			name:     "Data file of every key",
+			paths:    []string{"db.json"},
+			expected: []source{{filename: "db.json"}},
+		},
+		{
+			name:     "Data file of a single key",
+			paths:    []string{"posts.csv"},
+			expected: []source{{filename: "posts.csv", key: "posts"}},
+		},
+		{
+			name:  "Directory of data files",
+			paths: []string{"fixtures"},
+			expected: []source{
+				{filename: filepath.Join("fixtures", "authors.yaml"), key: "authors"},
+				{filename: filepath.Join("fixtures", "books.json"), key: "books"},
+				{filename: filepath.Join("fixtures", "seed.gen.json")},
+			},
+		},
+		{
+			name:  "Repeated files and directories",
+			paths: []string{"db.json", "posts.csv", "fixtures"},
+			expected: []source{
+				{filename: "db.json"},
+				{filename: "posts.csv", key: "posts"},
+				{filename: filepath.Join("fixtures", "authors.yaml"), key: "authors"},
+				{filename: filepath.Join("fixtures", "books.json"), key: "books"},
+				{filename: filepath.Join("fixtures", "seed.gen.json")},
+			},
+		},
+		{
+			name:  "Missing file",
+			paths: []string{"db.json", "missing.json"},
+			err:   errFileNotFound,
+		},
+	}
+
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testWriteFiles(t, dir, map[string]string{
+		"db.json":                   `{"posts": []}`,
+		"posts.csv":                 "id,title\n1,json-server\n",
+		"fixtures/books.json":       `[]`,
+		"fixtures/authors.yaml":     "[]\n",
+		"fixtures/seed.gen.json":    `{}`,
+		"fixtures/notes.txt":        "notes",
+		"fixtures/nested/tags.json": `[]`,
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			paths := make([]string, 0, len(tc.paths))
+			for _, path := range tc.paths {
+				paths = append(paths, filepath.Join(dir, path))
+			}
+
+			got, err := expandSources(paths)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, but got %v", tc.err, err)
+			}
+
+			if tc.err != nil {
+				return
+			}
+
+			expected := make([]source, 0, len(tc.expected))
+			for _, src := range tc.expected {
+				expected = append(expected, source{filename: filepath.Join(dir, src.filename), key: src.key})
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected sources %v, but got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestSourceKeys(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		paths            []string
+		expectedKeys     []string
+		expectedSingular []string
+		expectedRoutes   map[string]string
+		err              error
+	}{
+		{
+			name: "Resources of multiple files",
+			files: map[string]string{
+				"db.json":                `{"posts": [], "profile": {}}`,
+				"fixtures/books.json":    `[]`,
+				"fixtures/settings.yaml": "theme: dark\n",
+			},
+			paths:            []string{"db.json", "fixtures"},
+			expectedKeys:     []string{"books", "posts"},
+			expectedSingular: []string{"profile", "settings"},
+			expectedRoutes: map[string]string{
+				"posts":    "db.json",
+				"profile":  "db.json",
+				"books":    filepath.Join("fixtures", "books.json"),
+				"settings": filepath.Join("fixtures", "settings.yaml"),
+			},
+		},
+		{
+			name: "Resource in multiple files",
+			files: map[string]string{
+				"db.json":             `{"posts": [], "books": []}`,
+				"fixtures/books.json": `[]`,
+			},
+			paths: []string{"db.json", "fixtures"},
+			err:   errDuplicateResource,
+		},
+		{
+			name: "Singular resource in multiple files",
+			files: map[string]string{
+				"db.json":    `{"profile": {}}`,
+				"other.json": `{"profile": {"name": "typicode"}}`,
+			},
+			paths: []string{"db.json", "other.json"},
+			err:   errDuplicateResource,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testWriteFiles(t, dir, tc.files)
+
+			paths := make([]string, 0, len(tc.paths))
+			for _, path := range tc.paths {
+				paths = append(paths, filepath.Join(dir, path))
+			}
+
+			sources, err := expandSources(paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			keys, singular, routes, err := sourceKeys(sources, nil)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, but got %v", tc.err, err)
+			}
+
+			if tc.err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(keys, tc.expectedKeys) {
+				t.Fatalf("expected keys %v, but got %v", tc.expectedKeys, keys)
+			}
+
+			if !reflect.DeepEqual(singular, tc.expectedSingular) {
+				t.Fatalf("expected singular keys %v, but got %v", tc.expectedSingular, singular)
+			}
+
+			gotRoutes := make(map[string]string, len(routes))
+			for key, src := range routes {
+				gotRoutes[key], _ = filepath.Rel(dir, src.filename)
+			}
+
+			if !reflect.DeepEqual(gotRoutes, tc.expectedRoutes) {
+				t.Fatalf("expected routes %v, but got %v", tc.expectedRoutes, gotRoutes)
+			}
+		})
+	}
+}
+
+func TestStartFileFlag(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "Default file",
+			args:     []string{},
+			expected: []string{"db.json"},
+		},
+		{
+			name:     "Repeated files",
+			args:     []string{"--file", "db.json", "-f", "fixtures"},
+			expected: []string{"db.json", "fixtures"},
+		},
+		{
+			name:     "Comma separated files",
+			args:     []string{"--file", "db.json,fixtures"},
+			expected: []string{"db.json", "fixtures"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			startCmd := newStartCmd()
+			if err := startCmd.ParseFlags(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := startCmd.Flags().GetStringSlice("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected files %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestStorageEngine_Sources(t *testing.T) {
+	testCases := []struct {
+		name   string
+		engine string
+	}{
+		{name: "File storage engine", engine: storageFile},
+		{name: "Memory storage engine", engine: storageMemory},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testWriteFiles(t, dir, map[string]string{
+				"db.json":             `{"posts": [{"id": 1, "title": "json-server"}]}`,
+				"fixtures/books.json": `[{"id": 1, "title": "json-server in go"}]`,
+			})
+
+			file := filepath.Join(dir, "db.json")
+			booksFile := filepath.Join(dir, "fixtures", "books.json")
+
+			engine, err := newStorageEngine(tc.engine, []string{file, filepath.Join(dir, "fixtures")}, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = engine.setIDs("id", storage.IDGeneratorAutoIncrement, &config{}, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			resourceKeys, _, err := engine.keys()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resourceStorage, err := createResourceStorage(resourceKeys, engine)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = resourceStorage["books"].Create(storage.Resource{"title": "new book"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = resourceStorage["posts"].Create(storage.Resource{"title": "new post"}); err != nil {
+				t.Fatal(err)
+			}
+
+			expectedDB := storage.Database{
+				"posts": {
+					{"id": float64(1), "title": "json-server"},
+					{"id": float64(2), "title": "new post"},
+				},
+				"books": {
+					{"id": float64(1), "title": "json-server in go"},
+					{"id": float64(2), "title": "new book"},
+				},
+			}
+
+			// Resources of every file are merged for the db endpoint.
+			gotDB, err := resourceStorage["db"].DB()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(gotDB, expectedDB) {
+				t.Fatalf("expected data %v, but got %v", expectedDB, gotDB)
+			}
+
+			if err = engine.close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Changes are written to the file that each resource is defined in.
+			expectedContents := map[string]interface{}{
+				file: map[string]interface{}{
+					"posts": []interface{}{
+						map[string]interface{}{"id": float64(1), "title": "json-server"},
+						map[string]interface{}{"id": float64(2), "title": "new post"},
+					},
+				},
+				booksFile: []interface{}{
+					map[string]interface{}{"id": float64(1), "title": "json-server in go"},
+					map[string]interface{}{"id": float64(2), "title": "new book"},
+				},
+			}
+
+			for filename, expected := range expectedContents {
+				key := ""
+				if filename == booksFile {
+					key = "books"
+				}
+
+				got, err := storage.ReadContent(filename, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(got, expected) {
+					t.Fatalf("expected content of %s %v, but got %v", filename, expected, got)
+				}
+			}
+		})
+	}
+}
+
+// testWriteFiles writes the files with their contents, by path relative to dir, creating any
+// missing directories.
+func testWriteFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
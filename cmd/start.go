@@ -2,21 +2,21 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"math/rand"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/chanioxaris/json-server/internal/handler"
+	"github.com/chanioxaris/json-server/internal/inflection"
 	"github.com/chanioxaris/json-server/internal/logger"
 	"github.com/chanioxaris/json-server/internal/storage"
 )
@@ -25,11 +25,29 @@ var (
 	errFailedParseFlag     = errors.New("failed to parse flag")
 	errFailedParseFile     = errors.New("failed to parse file")
 	errFileNotFound        = errors.New("unable to find requested file")
-	errUnsupportedResource = errors.New("only array type resources are supported")
+	errUnsupportedResource = errors.New("only array and object type resources are supported")
 	errFailedStartServer   = errors.New("failed to start JSON server. Maybe port already in use")
 	errFailedInitResources = errors.New("failed to initialize resources")
+	errUnsupportedStorage  = errors.New("unsupported storage engine")
+	errInvalidIDGenerator  = errors.New("invalid id generator")
+	errUnsupportedDatabase = errors.New("unsupported database url")
+	errFailedOpenDatabase  = errors.New("failed to open database")
+	errConflictingFlags    = errors.New("conflicting flags")
 )
 
+const (
+	storageFile   = "file"
+	storageMemory = "memory"
+	storageSQLite = "sqlite"
+	storageBolt   = "bolt"
+)
+
+// databaseSchemes maps the schemes of database urls, e.g. 'sqlite://data.db', to storage engines.
+var databaseSchemes = map[string]string{
+	"sqlite://": storageSQLite,
+	"bolt://":   storageBolt,
+}
+
 func newStartCmd() *cobra.Command {
 	// startCmd represents the start command.
 	startCmd := &cobra.Command{
@@ -38,31 +56,49 @@ func newStartCmd() *cobra.Command {
 		Long: `
 For every provided resource, specific http endpoints are created. 
 Those include a GET, GET by ID, POST, PUT by ID, PATCH by ID and DELETE by ID. 
+Object data type resources are served as singular resources, with a GET, PUT and PATCH endpoint. 
 Also a '/db' endpoint is available that returns all the data. 
-Please note that only array data type resources are supported`,
+Please note that only array and object data type resources are supported`,
 		RunE: runStart,
 	}
 
 	// Optional flag to set the server port.
 	startCmd.Flags().StringP("port", "p", "3000", "Port the server will listen to")
-	// Optional flag to set the watch file.
-	startCmd.Flags().StringP("file", "f", "db.json", "File to watch")
+	// Optional flag to set the watch files, which can be repeated.
+	startCmd.Flags().StringSliceP("file", "f", []string{"db.json"}, "File or directory of data files to watch, in json, json5, yaml, toml or csv format, can be repeated")
 	// Optional flag to enable logs.
 	startCmd.Flags().BoolP("logs", "l", false, "Enable logs")
+	// Optional flag to set the storage engine.
+	startCmd.Flags().String("storage", storageFile, "Storage engine of the watch files, one of 'file' or 'memory', can't be combined with --db")
+	// Optional flag to set the interval to flush changes to file, for the memory storage engine.
+	startCmd.Flags().Duration("flush-interval", time.Second, "Interval to flush changes to file, for the memory storage engine")
+	// Optional flag to set the database url, which takes precedence over the watch files.
+	startCmd.Flags().String("db", "", "Database url to serve resources from, e.g. sqlite://data.db or bolt://data.db")
+	// Optional flag to set the interval to check the watch file for changes.
+	startCmd.Flags().Duration("watch-interval", time.Second, "Interval to check the watch file for changes, 0 disables reloading")
+	// Optional flag to set the config file.
+	startCmd.Flags().StringP("config", "c", "", "Config file with settings per resource")
+	// Optional flag to set the name of the id field of resources.
+	startCmd.Flags().String("id", "id", "Name of the id field of resources")
+	// Optional flags to set the generator of ids for new resources.
+	startCmd.Flags().String("id-generator", storage.IDGeneratorAutoIncrement, "Generator of ids for new resources, one of 'autoincrement', 'uuid', 'ulid', 'nanoid' or a template like 'usr_{seq}'")
+	startCmd.Flags().StringToString("id-generators", nil, "Generator of ids per resource, e.g. users=uuid,orders=ord_{seq}")
+	// Optional flag to set the seed of generated resources.
+	startCmd.Flags().Int64("seed", 0, "Seed of resources generated from '*.gen.*' files, random if not set")
+	// Optional flag to set irregular plural forms of resource names.
+	startCmd.Flags().StringToString("plural", nil, "Irregular plural forms of resource names, e.g. person=people")
 
 	return startCmd
 }
 
 func runStart(cmd *cobra.Command, _ []string) error {
-	rand.Seed(time.Now().UnixNano())
-
 	// Parse command's flags.
 	port, err := cmd.Flags().GetString("port")
 	if err != nil {
 		return fmt.Errorf("%w: port", errFailedParseFlag)
 	}
 
-	file, err := cmd.Flags().GetString("file")
+	files, err := cmd.Flags().GetStringSlice("file")
 	if err != nil {
 		return fmt.Errorf("%w: file", errFailedParseFlag)
 	}
@@ -72,25 +108,111 @@ func runStart(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("%w: logs", errFailedParseFlag)
 	}
 
+	plural, err := cmd.Flags().GetStringToString("plural")
+	if err != nil {
+		return fmt.Errorf("%w: plural", errFailedParseFlag)
+	}
+
+	engineName, err := cmd.Flags().GetString("storage")
+	if err != nil {
+		return fmt.Errorf("%w: storage", errFailedParseFlag)
+	}
+
+	dbURL, err := cmd.Flags().GetString("db")
+	if err != nil {
+		return fmt.Errorf("%w: db", errFailedParseFlag)
+	}
+
+	flushInterval, err := cmd.Flags().GetDuration("flush-interval")
+	if err != nil {
+		return fmt.Errorf("%w: flush-interval", errFailedParseFlag)
+	}
+
+	watchInterval, err := cmd.Flags().GetDuration("watch-interval")
+	if err != nil {
+		return fmt.Errorf("%w: watch-interval", errFailedParseFlag)
+	}
+
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("%w: config", errFailedParseFlag)
+	}
+
+	idField, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return fmt.Errorf("%w: id", errFailedParseFlag)
+	}
+
+	idGenerator, err := cmd.Flags().GetString("id-generator")
+	if err != nil {
+		return fmt.Errorf("%w: id-generator", errFailedParseFlag)
+	}
+
+	idGenerators, err := cmd.Flags().GetStringToString("id-generators")
+	if err != nil {
+		return fmt.Errorf("%w: id-generators", errFailedParseFlag)
+	}
+
+	seed, err := cmd.Flags().GetInt64("seed")
+	if err != nil {
+		return fmt.Errorf("%w: seed", errFailedParseFlag)
+	}
+
+	if !cmd.Flags().Changed("seed") {
+		seed = time.Now().UnixNano()
+	}
+
 	// Setup logger.
 	logger.Setup(logs)
 
-	// Get resource keys.
-	resourceKeys, err := getResourceKeys(file)
+	// Read settings per resource.
+	cfg, err := readConfig(configFile)
 	if err != nil {
 		return err
 	}
 
-	// Create storage service for each resource.
-	resourceStorage, err := createResourceStorage(resourceKeys, file)
+	// Setup storage engine. A database takes precedence over the watch files.
+	var engine *storageEngine
+	if dbURL != "" && cmd.Flags().Changed("storage") {
+		return fmt.Errorf("%w: storage and db", errConflictingFlags)
+	}
+
+	if dbURL != "" {
+		databaseEngine, filename, err := parseDatabaseURL(dbURL)
+		if err != nil {
+			return err
+		}
+
+		if engine, err = newDatabaseStorageEngine(databaseEngine, filename); err != nil {
+			return err
+		}
+	} else if engine, err = newStorageEngine(engineName, files, flushInterval); err != nil {
+		return err
+	}
+
+	engine.seed = seed
+
+	if err = engine.setIDs(idField, idGenerator, cfg, idGenerators); err != nil {
+		return err
+	}
+
+	engine.setIndexes(cfg)
+	engine.setParents(cfg)
+
+	inflector := inflection.New(plural)
+
+	// Setup API handler based on the resources of the storage engine.
+	apiHandler, keys, err := setupHandler(engine, inflector)
 	if err != nil {
 		return err
 	}
 
+	router := newSwappableHandler(apiHandler)
+
 	// Setup API server.
 	api := &http.Server{
 		Addr:    ":" + port,
-		Handler: handler.Setup(resourceStorage),
+		Handler: router,
 		// Good practice to set timeouts to avoid Slowloris attacks.
 		WriteTimeout: time.Second * 15,
 		ReadTimeout:  time.Second * 15,
@@ -107,15 +229,75 @@ func runStart(cmd *cobra.Command, _ []string) error {
 	go api.Serve(listener)
 
 	// Display info about available resources and home page.
-	displayInfo(resourceKeys, port)
+	displayInfo(keys, port)
 
-	gracefulShutdown(api)
+	// Display the seed of generated resources, so they can be reproduced.
+	if len(engine.generated) > 0 {
+		fmt.Printf("Generated resources with seed %d\n\n", seed)
+	}
+
+	// Reload resources when any of the watch files changes. On failure, the last good resources are kept.
+	stopWatch := func() {}
+	if watchInterval > 0 && dbURL == "" {
+		watched := strings.Join(files, ", ")
+
+		stopWatch = watchFiles(files, watchInterval, func() {
+			newKeys, err := reloadResources(engine, inflector, router)
+			if err != nil {
+				fmt.Printf("failed to reload %s: %v\n", watched, err)
+			}
+
+			if newKeys == nil {
+				return
+			}
+
+			// Display info only when resources were added or removed.
+			if !reflect.DeepEqual(keys, newKeys) {
+				keys = newKeys
+
+				fmt.Printf("Reloaded resources of %s\n\n", watched)
+				displayResources(keys, port)
+			}
+		})
+	}
+
+	gracefulShutdown(api, engine, stopWatch)
 
 	return nil
 }
 
+// setupHandler creates the storage services for the resources of the storage engine, and returns the
+// API handler along with the resource keys.
+func setupHandler(engine *storageEngine, inflector *inflection.Inflector) (http.Handler, []string, error) {
+	// Get resource keys.
+	resourceKeys, singularKeys, err := engine.keys()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create storage service for each resource.
+	resourceStorage, err := createResourceStorage(resourceKeys, engine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create storage service for each singular resource.
+	singularStorage, err := createSingularStorage(singularKeys, engine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := handler.Config{
+		Inflector: inflector,
+		Singular:  singularStorage,
+		Parents:   engine.parents,
+	}
+
+	return handler.Setup(resourceStorage, cfg), append(resourceKeys, singularKeys...), nil
+}
+
 // gracefulShutdown handles any signal that interrupts the running server
-func gracefulShutdown(server *http.Server) {
+func gracefulShutdown(server *http.Server, engine *storageEngine, stopWatch func()) {
 	c := make(chan os.Signal, 1)
 	// We'll accept graceful shutdowns when quit via SIGINT (Ctrl+C)
 	// SIGKILL, SIGQUIT or SIGTERM (Ctrl+/) will not be caught.
@@ -134,41 +316,545 @@ func gracefulShutdown(server *http.Server) {
 		return
 	}
 
+	// Stop reloading resources, before releasing storage.
+	stopWatch()
+
+	// Persist any pending changes of storage.
+	if err := engine.close(); err != nil {
+		fmt.Println("failed to persist storage changes")
+		return
+	}
+
 	fmt.Println("gracefully shutting down server")
 }
 
-func getResourceKeys(filename string) ([]string, error) {
+func getResourceKeys(filename string) ([]string, []string, error) {
 	// Read file contents used as storage.
-	contentBytes, err := ioutil.ReadFile(filename)
+	value, err := readContent(filename, "")
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", errFileNotFound, filename)
+		return nil, nil, err
 	}
 
-	content := map[string]interface{}{}
-	if err = json.Unmarshal(contentBytes, &content); err != nil {
-		return nil, fmt.Errorf("%w: %s", errFailedParseFile, filename)
+	content, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", errFailedParseFile, filename)
 	}
 
 	resourceKeys := make([]string, 0)
+	singularKeys := make([]string, 0)
 
 	// Range on content to retrieve resource keys.
 	for resource, data := range content {
+		if data == nil {
+			return nil, nil, errUnsupportedResource
+		}
+
 		switch reflect.TypeOf(data).Kind() {
 		case reflect.Slice:
 			resourceKeys = append(resourceKeys, resource)
+		case reflect.Map:
+			singularKeys = append(singularKeys, resource)
 		default:
-			return nil, errUnsupportedResource
+			return nil, nil, errUnsupportedResource
+		}
+	}
+
+	sort.Strings(resourceKeys)
+	sort.Strings(singularKeys)
+
+	return resourceKeys, singularKeys, nil
+}
+
+// storageEngine creates the storage services of resources, for the selected storage engine.
+type storageEngine struct {
+	newStorage  func(key string, opts ...storage.Option) (storage.Storage, error)
+	newSingular func(key string) (storage.SingularStorage, error)
+	// keys returns the keys of resources and singular resources.
+	keys func() ([]string, []string, error)
+	// importFile replaces the contents of a database with the resources of a data file, if supported.
+	importFile func(filename string) error
+	// reload refreshes any cached contents, after the file is changed externally.
+	reload func() error
+	// close releases the storage engine, persisting any pending changes.
+	close func() error
+
+	// sources are the data files of resources, as of the last keys, along with the source of every key.
+	sources []source
+	routes  map[string]source
+	// generated are the documents of the resources generated from generator files, with the seed.
+	// They are served from memory, whatever the storage engine, and never written.
+	generated map[source]*storage.MemoryDocument
+	seed      int64
+	// snapshot are the documents of the last valid contents of the sources, which are served from memory
+	// while any of the files is invalid. Changes to them are never written.
+	snapshot map[source]*storage.MemoryDocument
+
+	// idField is the name of the id field of resources, unless overridden per resource key.
+	idField  string
+	idFields map[string]string
+	// idGenerator generates the ids of new resources, unless overridden per resource key.
+	idGenerator  storage.IDGenerator
+	idGenerators map[string]storage.IDGenerator
+	// indexes are the fields that resources are indexed by, per resource key.
+	indexes map[string][]string
+	// parents are the resources that resources are nested under, per resource key.
+	parents map[string][]string
+}
+
+// newStorageEngine returns the storage engine of the data files of paths, which are files or directories.
+func newStorageEngine(engine string, paths []string, flushInterval time.Duration) (*storageEngine, error) {
+	switch engine {
+	case storageFile:
+		// contents are the last valid contents of the sources, as of the last keys or reload.
+		var contents map[source]interface{}
+
+		e := &storageEngine{
+			close: func() error { return nil },
+		}
+
+		e.keys = func() ([]string, []string, error) {
+			if e.snapshot != nil {
+				resourceKeys, singularKeys, _, err := sourceKeys(e.sources, e.snapshot)
+				return resourceKeys, singularKeys, err
+			}
+
+			resourceKeys, singularKeys, err := e.setSources(paths)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if contents == nil {
+				if contents, err = readSources(e.sources); err != nil {
+					return nil, nil, err
+				}
+			}
+
+			return resourceKeys, singularKeys, nil
+		}
+
+		// Resources are read from the files on every request, so while any of them is invalid, the last
+		// valid contents are served from memory instead.
+		e.reload = func() error {
+			sources, err := expandSources(paths)
+			if err != nil {
+				return e.serveSnapshot(contents, err)
+			}
+
+			newContents, err := readSources(sources)
+			if err != nil {
+				return e.serveSnapshot(contents, err)
+			}
+
+			contents = newContents
+			e.snapshot = nil
+
+			return nil
+		}
+
+		e.newStorage = func(key string, opts ...storage.Option) (storage.Storage, error) {
+			if key == "" {
+				return e.sourcesStorage(func(src source) (storage.Storage, error) {
+					if doc, ok := e.memoryDoc(src); ok {
+						return storage.NewMemory(doc, "")
+					}
+
+					if src.key != "" {
+						return storage.NewResourceFile(src.filename, src.key)
+					}
+
+					return storage.NewFile(src.filename, "")
+				})
+			}
+
+			if doc, ok := e.memoryDoc(e.routes[key]); ok {
+				return storage.NewMemory(doc, key, opts...)
+			}
+
+			if src := e.routes[key]; src.key != "" {
+				return storage.NewResourceFile(src.filename, key, opts...)
+			}
+
+			return storage.NewFile(e.routes[key].filename, key, opts...)
+		}
+
+		e.newSingular = func(key string) (storage.SingularStorage, error) {
+			if doc, ok := e.memoryDoc(e.routes[key]); ok {
+				return storage.NewSingularMemory(doc, key)
+			}
+
+			if src := e.routes[key]; src.key != "" {
+				return storage.NewSingularResourceFile(src.filename, key)
+			}
+
+			return storage.NewSingularFile(e.routes[key].filename, key)
+		}
+
+		return e, nil
+	case storageMemory:
+		// Every source is loaded in its own memory document.
+		docs := make(map[source]*storage.MemoryDocument)
+
+		e := &storageEngine{}
+
+		e.keys = func() ([]string, []string, error) {
+			resourceKeys, singularKeys, err := e.setSources(paths)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			current := make(map[source]bool, len(e.sources))
+			for _, src := range e.sources {
+				current[src] = true
+
+				if _, ok := docs[src]; ok {
+					continue
+				}
+
+				if doc, ok := e.generated[src]; ok {
+					docs[src] = doc
+					continue
+				}
+
+				var doc *storage.MemoryDocument
+				if src.key != "" {
+					doc, err = storage.NewResourceMemoryDocument(src.filename, src.key, flushInterval)
+				} else {
+					doc, err = storage.NewMemoryDocument(src.filename, flushInterval)
+				}
+				if err != nil {
+					return nil, nil, fmt.Errorf("%w: %s", errFailedParseFile, src.filename)
+				}
+
+				docs[src] = doc
+			}
+
+			// Documents of removed files are released, persisting any pending changes.
+			for src, doc := range docs {
+				if !current[src] {
+					doc.Close()
+					delete(docs, src)
+				}
+			}
+
+			return resourceKeys, singularKeys, nil
+		}
+
+		e.newStorage = func(key string, opts ...storage.Option) (storage.Storage, error) {
+			if key == "" {
+				return e.sourcesStorage(func(src source) (storage.Storage, error) {
+					return storage.NewMemory(docs[src], "")
+				})
+			}
+
+			return storage.NewMemory(docs[e.routes[key]], key, opts...)
+		}
+
+		e.newSingular = func(key string) (storage.SingularStorage, error) {
+			return storage.NewSingularMemory(docs[e.routes[key]], key)
+		}
+
+		e.reload = func() error {
+			sources, err := expandSources(paths)
+			if err != nil {
+				return err
+			}
+
+			// Removed files are released on the following keys.
+			for _, src := range sources {
+				if doc, ok := docs[src]; ok {
+					if err = doc.Reload(); err != nil {
+						return err
+					}
+				}
+			}
+
+			return nil
+		}
+
+		e.close = func() error {
+			var closeErr error
+			for _, doc := range docs {
+				if err := doc.Close(); err != nil {
+					closeErr = err
+				}
+			}
+
+			return closeErr
+		}
+
+		return e, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedStorage, engine)
+	}
+}
+
+// newDatabaseStorageEngine returns the storage engine of a database file.
+func newDatabaseStorageEngine(engine, filename string) (*storageEngine, error) {
+	switch engine {
+	case storageSQLite:
+		database, err := storage.NewSQLiteDatabase(filename)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errFailedOpenDatabase, filename)
+		}
+
+		return &storageEngine{
+			newStorage: func(key string, opts ...storage.Option) (storage.Storage, error) {
+				return storage.NewSQLite(database, key, opts...)
+			},
+			newSingular: func(key string) (storage.SingularStorage, error) {
+				return storage.NewSingularSQLite(database, key)
+			},
+			keys:       database.Keys,
+			importFile: database.Import,
+			reload:     func() error { return nil },
+			close:      database.Close,
+		}, nil
+	case storageBolt:
+		database, err := storage.NewBoltDatabase(filename)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errFailedOpenDatabase, filename)
+		}
+
+		return &storageEngine{
+			newStorage: func(key string, opts ...storage.Option) (storage.Storage, error) {
+				return storage.NewBolt(database, key, opts...)
+			},
+			newSingular: func(key string) (storage.SingularStorage, error) {
+				return storage.NewSingularBolt(database, key)
+			},
+			keys:       database.Keys,
+			importFile: database.Import,
+			reload:     func() error { return nil },
+			close:      database.Close,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedStorage, engine)
+	}
+}
+
+// setSources sets the sources of the data files of paths, and returns the keys of their resources and
+// singular resources.
+func (e *storageEngine) setSources(paths []string) ([]string, []string, error) {
+	sources, err := expandSources(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = e.generate(sources); err != nil {
+		return nil, nil, err
+	}
+
+	resourceKeys, singularKeys, routes, err := sourceKeys(sources, e.generated)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e.sources = sources
+	e.routes = routes
+
+	return resourceKeys, singularKeys, nil
+}
+
+// generate sets the documents of the resources generated from the generator files of the sources. Resources
+// are generated once, so that changes made through the API are kept while the server is running.
+func (e *storageEngine) generate(sources []source) error {
+	generated := make(map[source]*storage.MemoryDocument)
+	for _, src := range sources {
+		if !isGeneratorFile(src.filename) {
+			continue
+		}
+
+		if doc, ok := e.generated[src]; ok {
+			generated[src] = doc
+			continue
+		}
+
+		content, err := generateContent(src.filename, e.seed)
+		if err != nil {
+			return err
+		}
+
+		doc, err := storage.NewContentMemoryDocument(content)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errFailedGenerate, src.filename)
 		}
+
+		generated[src] = doc
+	}
+
+	e.generated = generated
+
+	return nil
+}
+
+// memoryDoc returns the memory document that the resources of the source are served from, if any, which
+// is either the one of a generator file or the snapshot of the last valid contents of the source.
+func (e *storageEngine) memoryDoc(src source) (*storage.MemoryDocument, bool) {
+	if doc, ok := e.generated[src]; ok {
+		return doc, true
 	}
 
-	return resourceKeys, nil
+	doc, ok := e.snapshot[src]
+
+	return doc, ok
 }
 
-func createResourceStorage(resourceKeys []string, filename string) (map[string]storage.Storage, error) {
+// serveSnapshot serves the last valid contents of the sources from memory, after they failed to be read
+// with err, which is returned. A snapshot is taken once, so that changes made to it are kept until the
+// files are fixed.
+func (e *storageEngine) serveSnapshot(contents map[source]interface{}, err error) error {
+	if contents == nil || e.snapshot != nil {
+		return err
+	}
+
+	snapshot := make(map[source]*storage.MemoryDocument, len(e.sources))
+	for _, src := range e.sources {
+		if doc, ok := e.generated[src]; ok {
+			snapshot[src] = doc
+			continue
+		}
+
+		content, ok := contents[src].(map[string]interface{})
+		if src.key != "" {
+			content, ok = map[string]interface{}{src.key: contents[src]}, true
+		}
+
+		if !ok {
+			return err
+		}
+
+		doc, docErr := storage.NewContentMemoryDocument(content)
+		if docErr != nil {
+			return err
+		}
+
+		snapshot[src] = doc
+	}
+
+	e.snapshot = snapshot
+
+	return err
+}
+
+// sourcesStorage returns the storage of all the resources of the sources, merging them if there are
+// multiple sources.
+func (e *storageEngine) sourcesStorage(newStorage func(src source) (storage.Storage, error)) (storage.Storage, error) {
+	storages := make([]storage.Storage, 0, len(e.sources))
+	for _, src := range e.sources {
+		storageSvc, err := newStorage(src)
+		if err != nil {
+			return nil, err
+		}
+
+		storages = append(storages, storageSvc)
+	}
+
+	if len(storages) == 1 {
+		return storages[0], nil
+	}
+
+	return storage.NewMerged(storages...)
+}
+
+// parseDatabaseURL returns the storage engine and the database file of the url.
+func parseDatabaseURL(dbURL string) (string, string, error) {
+	for scheme, engine := range databaseSchemes {
+		if filename := strings.TrimPrefix(dbURL, scheme); filename != dbURL && filename != "" {
+			return engine, filename, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: %s", errUnsupportedDatabase, dbURL)
+}
+
+// setIDs sets the default id field and generator, along with any overrides per resource key from the
+// config file. Id generators per resource key from flags take precedence over the config file.
+func (e *storageEngine) setIDs(idField, strategy string, cfg *config, strategies map[string]string) error {
+	idGenerator, err := storage.NewIDGenerator(strategy)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidIDGenerator, strategy)
+	}
+
+	e.idField = idField
+	e.idFields = make(map[string]string)
+	e.idGenerator = idGenerator
+	e.idGenerators = make(map[string]storage.IDGenerator)
+
+	resourceStrategies := make(map[string]string)
+	for key, resourceCfg := range cfg.Resources {
+		if resourceCfg.ID != "" {
+			e.idFields[key] = resourceCfg.ID
+		}
+
+		if resourceCfg.IDGenerator != "" {
+			resourceStrategies[key] = resourceCfg.IDGenerator
+		}
+	}
+
+	for key, strategy := range strategies {
+		resourceStrategies[key] = strategy
+	}
+
+	for key, strategy := range resourceStrategies {
+		idGenerator, err := storage.NewIDGenerator(strategy)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errInvalidIDGenerator, strategy)
+		}
+
+		e.idGenerators[key] = idGenerator
+	}
+
+	return nil
+}
+
+// setIndexes sets the fields that resources are indexed by, per resource key from the config file.
+func (e *storageEngine) setIndexes(cfg *config) {
+	e.indexes = make(map[string][]string)
+	for key, resourceCfg := range cfg.Resources {
+		if len(resourceCfg.Indexes) > 0 {
+			e.indexes[key] = resourceCfg.Indexes
+		}
+	}
+}
+
+// setParents sets the resources that resources are nested under, per resource key from the config file.
+func (e *storageEngine) setParents(cfg *config) {
+	e.parents = make(map[string][]string)
+	for key, resourceCfg := range cfg.Resources {
+		if len(resourceCfg.Parents) > 0 {
+			e.parents[key] = resourceCfg.Parents
+		}
+	}
+}
+
+// storageOptions returns the options of the storage service for the resource key.
+func (e *storageEngine) storageOptions(key string) []storage.Option {
+	opts := make([]storage.Option, 0, 3)
+
+	if idField, ok := e.idFields[key]; ok {
+		opts = append(opts, storage.WithIDField(idField))
+	} else if e.idField != "" {
+		opts = append(opts, storage.WithIDField(e.idField))
+	}
+
+	if idGenerator, ok := e.idGenerators[key]; ok {
+		opts = append(opts, storage.WithIDGenerator(idGenerator))
+	} else if e.idGenerator != nil {
+		opts = append(opts, storage.WithIDGenerator(e.idGenerator))
+	}
+
+	if indexes, ok := e.indexes[key]; ok {
+		opts = append(opts, storage.WithIndexes(indexes...))
+	}
+
+	return opts
+}
+
+func createResourceStorage(resourceKeys []string, engine *storageEngine) (map[string]storage.Storage, error) {
 	resourceStorage := make(map[string]storage.Storage)
 
 	for _, resourceKey := range resourceKeys {
-		storageSvc, err := storage.NewFile(filename, resourceKey)
+		storageSvc, err := engine.newStorage(resourceKey, engine.storageOptions(resourceKey)...)
 		if err != nil {
 			return nil, errFailedInitResources
 		}
@@ -177,7 +863,7 @@ func createResourceStorage(resourceKeys []string, filename string) (map[string]s
 	}
 
 	// Create storage service for common db endpoint.
-	storageSvcDB, err := storage.NewFile(filename, "")
+	storageSvcDB, err := engine.newStorage("")
 	if err != nil {
 		return nil, errFailedInitResources
 	}
@@ -187,16 +873,35 @@ func createResourceStorage(resourceKeys []string, filename string) (map[string]s
 	return resourceStorage, nil
 }
 
+func createSingularStorage(singularKeys []string, engine *storageEngine) (map[string]storage.SingularStorage, error) {
+	singularStorage := make(map[string]storage.SingularStorage)
+
+	for _, singularKey := range singularKeys {
+		singularSvc, err := engine.newSingular(singularKey)
+		if err != nil {
+			return nil, errFailedInitResources
+		}
+
+		singularStorage[singularKey] = singularSvc
+	}
+
+	return singularStorage, nil
+}
+
 func displayInfo(resourceKeys []string, port string) {
 	fmt.Printf("JSON Server successfully running\n\n")
 
+	displayResources(resourceKeys, port)
+
+	fmt.Println("Home")
+	fmt.Printf("http://localhost:%s\n\n", port)
+}
+
+func displayResources(resourceKeys []string, port string) {
 	fmt.Println("Resources")
 	for _, resource := range resourceKeys {
 		fmt.Printf("http://localhost:%s/%s\n", port, resource)
 	}
 
 	fmt.Printf("http://localhost:%s/db\n\n", port)
-
-	fmt.Println("Home")
-	fmt.Printf("http://localhost:%s\n\n", port)
 }
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+	"github.com/chanioxaris/json-server/internal/validation"
+)
+
+var errInvalidResources = errors.New("invalid resources")
+
+func newValidateCmd() *cobra.Command {
+	// validateCmd represents the validate command.
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Report every problem of the resources of data files",
+		Long: `
+Reports every problem of the resources of data files at once, like syntax errors with
+their line and column, unsupported resource types, records missing ids, duplicate ids,
+mixed id types and foreign keys that refer to missing resources. Exits with a non zero
+status if there are any problems, e.g. to be used in CI.`,
+		RunE: runValidate,
+		// Problems are reported on their own, without usage.
+		SilenceUsage: true,
+	}
+
+	// Optional flag to set the data files, which can be repeated.
+	validateCmd.Flags().StringSliceP("file", "f", []string{"db.json"}, "File or directory of data files to validate, can be repeated")
+	// Optional flag to set the config file.
+	validateCmd.Flags().StringP("config", "c", "", "Config file with settings per resource")
+	// Optional flag to set the name of the id field of resources.
+	validateCmd.Flags().String("id", "id", "Name of the id field of resources")
+	// Optional flag to set irregular plural forms of resource names.
+	validateCmd.Flags().StringToString("plural", nil, "Irregular plural forms of resource names, e.g. person=people")
+
+	return validateCmd
+}
+
+func runValidate(cmd *cobra.Command, _ []string) error {
+	// Parse command's flags.
+	files, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return fmt.Errorf("%w: file", errFailedParseFlag)
+	}
+
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("%w: config", errFailedParseFlag)
+	}
+
+	idField, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return fmt.Errorf("%w: id", errFailedParseFlag)
+	}
+
+	plural, err := cmd.Flags().GetStringToString("plural")
+	if err != nil {
+		return fmt.Errorf("%w: plural", errFailedParseFlag)
+	}
+
+	// Read settings per resource.
+	cfg, err := readConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	idFields := make(map[string]string)
+	for key, resourceCfg := range cfg.Resources {
+		if resourceCfg.ID != "" {
+			idFields[key] = resourceCfg.ID
+		}
+	}
+
+	sources, err := expandSources(files)
+	if err != nil {
+		return err
+	}
+
+	problems := make([]validation.Problem, 0)
+	validSources := make([]validation.Source, 0, len(sources))
+	for _, src := range sources {
+		// Generator files contain templates, instead of resources.
+		if isGeneratorFile(src.filename) {
+			fmt.Printf("Skipped generator file %s\n", src.filename)
+			continue
+		}
+
+		validSource, fileProblems := validation.Read(src.filename, src.key)
+		if len(fileProblems) > 0 {
+			problems = append(problems, fileProblems...)
+			continue
+		}
+
+		validSources = append(validSources, validSource)
+	}
+
+	validator := validation.New(idField, idFields, inflection.New(plural))
+	problems = append(problems, validator.Validate(validSources)...)
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: found %d problems", errInvalidResources, len(problems))
+	}
+
+	fmt.Printf("No problems found in %d files\n", len(validSources))
+
+	return nil
+}
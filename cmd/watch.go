@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+)
+
+// swappableHandler serves requests with the current handler, which can be swapped atomically.
+// In-flight requests complete with the handler they started with.
+type swappableHandler struct {
+	current atomic.Value
+}
+
+func newSwappableHandler(h http.Handler) *swappableHandler {
+	s := &swappableHandler{}
+	s.swap(h)
+
+	return s
+}
+
+func (s *swappableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.current.Load().(http.Handler).ServeHTTP(w, r)
+}
+
+func (s *swappableHandler) swap(h http.Handler) {
+	s.current.Store(h)
+}
+
+// reloadResources reloads the resources of the storage engine, and swaps the handler of the router with
+// the one of the reloaded resources, returning their keys. On failure, the last good resources are kept:
+// the file storage engine serves them from memory until the files are fixed, along with their keys,
+// while the rest of the engines keep the current handler, without any keys.
+func reloadResources(engine *storageEngine, inflector *inflection.Inflector, router *swappableHandler) ([]string, error) {
+	reloadErr := engine.reload()
+
+	apiHandler, keys, err := setupHandler(engine, inflector)
+	if err != nil {
+		if reloadErr != nil {
+			return nil, reloadErr
+		}
+
+		return nil, err
+	}
+
+	router.swap(apiHandler)
+
+	return keys, reloadErr
+}
+
+// watchFiles polls the files every interval, and calls onChange whenever the modification time or
+// size of any of them changes. Directories are watched for changes of their data files, including
+// added and removed ones. It returns a function that stops watching.
+func watchFiles(paths []string, interval time.Duration, onChange func()) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last, _ := statFiles(paths)
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				current, err := statFiles(paths)
+				if err != nil {
+					continue
+				}
+
+				if last != nil && reflect.DeepEqual(current, last) {
+					continue
+				}
+
+				last = current
+				onChange()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// fileState is the modification time and size of a file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFiles returns the state of the files of paths, where directories are expanded to their data files.
+func statFiles(paths []string) (map[string]fileState, error) {
+	sources, err := expandSources(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]fileState, len(sources))
+	for _, src := range sources {
+		info, err := os.Stat(src.filename)
+		if err != nil {
+			return nil, err
+		}
+
+		states[src.filename] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return states, nil
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+)
+
+func TestSwappableHandler(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := newSwappableHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("old"))
+	}))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	inFlight := make(chan string)
+	go func() {
+		inFlight <- testGetBody(t, server.URL)
+	}()
+
+	<-started
+
+	router.swap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("new"))
+	}))
+
+	if got := testGetBody(t, server.URL); got != "new" {
+		t.Fatalf("expected body %q of swapped handler, but got %q", "new", got)
+	}
+
+	close(release)
+
+	if got := <-inFlight; got != "old" {
+		t.Fatalf("expected body %q of in-flight request, but got %q", "old", got)
+	}
+}
+
+func TestWatchFiles(t *testing.T) {
+	testCases := []struct {
+		name    string
+		change  func(file, dir string) error
+		changed bool
+	}{
+		{
+			name:    "Unchanged files",
+			change:  func(string, string) error { return nil },
+			changed: false,
+		},
+		{
+			name: "Changed size of file",
+			change: func(file, _ string) error {
+				return ioutil.WriteFile(file, []byte(`{"posts": [{"id": 1}]}`), 0644)
+			},
+			changed: true,
+		},
+		{
+			name: "Changed modification time of file",
+			change: func(file, _ string) error {
+				modTime := time.Now().Add(time.Hour)
+				return os.Chtimes(file, modTime, modTime)
+			},
+			changed: true,
+		},
+		{
+			name: "Added data file in directory",
+			change: func(_, dir string) error {
+				return ioutil.WriteFile(filepath.Join(dir, "authors.json"), []byte(`[]`), 0644)
+			},
+			changed: true,
+		},
+		{
+			name: "Removed data file from directory",
+			change: func(_, dir string) error {
+				return os.Remove(filepath.Join(dir, "books.json"))
+			},
+			changed: true,
+		},
+		{
+			name: "Added other file in directory",
+			change: func(_, dir string) error {
+				return ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644)
+			},
+			changed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, "db.json")
+			if err = ioutil.WriteFile(file, []byte(`{"posts": []}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fixtures := filepath.Join(dir, "fixtures")
+			if err = os.Mkdir(fixtures, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = ioutil.WriteFile(filepath.Join(fixtures, "books.json"), []byte(`[]`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			changes := make(chan struct{}, 1)
+			stopWatch := watchFiles([]string{file, fixtures}, 10*time.Millisecond, func() {
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			})
+			defer stopWatch()
+
+			// Let the watcher record the initial state of the files.
+			time.Sleep(50 * time.Millisecond)
+
+			if err = tc.change(file, fixtures); err != nil {
+				t.Fatal(err)
+			}
+
+			var changed bool
+			select {
+			case <-changes:
+				changed = true
+			case <-time.After(500 * time.Millisecond):
+			}
+
+			if changed != tc.changed {
+				t.Fatalf("expected changed %v, but got %v", tc.changed, changed)
+			}
+		})
+	}
+}
+
+func TestReloadResources(t *testing.T) {
+	testCases := []struct {
+		name   string
+		engine string
+	}{
+		{name: "File storage engine", engine: storageFile},
+		{name: "Memory storage engine", engine: storageMemory},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, "db.json")
+			if err = ioutil.WriteFile(file, []byte(`{"posts": [{"id": 1, "title": "first"}]}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			engine, err := newStorageEngine(tc.engine, []string{file}, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer engine.close()
+
+			if err = engine.setIDs("id", "autoincrement", &config{}, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			inflector := inflection.New(nil)
+
+			apiHandler, _, err := setupHandler(engine, inflector)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := newSwappableHandler(apiHandler)
+
+			// Invalid contents keep the last good resources.
+			if err = ioutil.WriteFile(file, []byte(`{"posts": [`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = reloadResources(engine, inflector, router); err == nil {
+				t.Fatal("expected error of invalid contents, but got none")
+			}
+
+			expected := map[string]interface{}{"id": float64(1), "title": "first"}
+			if got := testGetResource(t, router, "/posts/1"); !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected resource %v, but got %v", expected, got)
+			}
+
+			// Valid contents replace the last good resources.
+			if err = ioutil.WriteFile(file, []byte(`{"posts": [{"id": 1, "title": "fixed"}], "users": []}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := reloadResources(engine, inflector, router)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expectedKeys := []string{"posts", "users"}; !reflect.DeepEqual(keys, expectedKeys) {
+				t.Fatalf("expected keys %v, but got %v", expectedKeys, keys)
+			}
+
+			expected = map[string]interface{}{"id": float64(1), "title": "fixed"}
+			if got := testGetResource(t, router, "/posts/1"); !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected resource %v, but got %v", expected, got)
+			}
+		})
+	}
+}
+
+// testGetBody returns the body of the response of a GET request to url.
+func testGetBody(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+
+	return string(bodyBytes)
+}
+
+// testGetResource returns the decoded body of the response of the handler to a GET request to path.
+func testGetResource(t *testing.T, h http.Handler, path string) interface{} {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status code %v, but got %v", http.StatusOK, rec.Code)
+	}
+
+	var body interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	return body
+}
@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// function returns a generated value, for the resource at the index and the arguments of a placeholder.
+type function func(r *rand.Rand, idx int, args []interface{}) (interface{}, error)
+
+// functions contains the functions of placeholders, by name.
+var functions = map[string]function{
+	"seq":       seq,
+	"int":       randomInt,
+	"float":     randomFloat,
+	"bool":      randomBool,
+	"pick":      pick,
+	"firstName": word(firstNames),
+	"lastName":  word(lastNames),
+	"name":      name,
+	"username":  username,
+	"email":     email,
+	"phone":     phone,
+	"company":   company,
+	"street":    street,
+	"city":      word(cities),
+	"country":   word(countries),
+	"word":      word(loremWords),
+	"sentence":  sentence,
+	"paragraph": paragraph,
+	"date":      date,
+	"uuid":      uuid,
+}
+
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "David", "Emma", "Frank", "Grace", "Henry", "Isla", "Jack", "Kate", "Liam", "Maria", "Noah", "Olivia", "Peter", "Quinn", "Rosa", "Sam", "Tara", "Umar", "Vera", "Will", "Xena", "Yusuf", "Zoe"}
+	lastNames  = []string{"Anderson", "Brown", "Clark", "Davis", "Evans", "Fischer", "Garcia", "Harris", "Ivanova", "Jones", "King", "Lopez", "Miller", "Nguyen", "Oliveira", "Papadopoulos", "Quinn", "Rossi", "Smith", "Taylor", "Ueda", "Walker", "Young", "Zimmerman"}
+	cities     = []string{"Amsterdam", "Athens", "Berlin", "Boston", "Buenos Aires", "Cairo", "Dublin", "Lisbon", "London", "Madrid", "Nairobi", "Oslo", "Paris", "Prague", "Seoul", "Sydney", "Tokyo", "Toronto", "Vienna", "Warsaw"}
+	countries  = []string{"Argentina", "Australia", "Austria", "Brazil", "Canada", "Egypt", "France", "Germany", "Greece", "Ireland", "Italy", "Japan", "Kenya", "Netherlands", "Norway", "Poland", "Portugal", "South Korea", "Spain", "United Kingdom", "United States"}
+	streets    = []string{"Main Street", "High Street", "Park Avenue", "Oak Lane", "Maple Drive", "Cedar Road", "Elm Street", "Hill Road", "Lake View", "River Walk"}
+	companies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Hooli", "Vandelay", "Wonka", "Cyberdyne"}
+	suffixes   = []string{"Inc", "LLC", "Ltd", "Group", "Labs"}
+	domains    = []string{"example.com", "example.org", "example.net"}
+	loremWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud"}
+)
+
+// numberArgs returns the numeric arguments, or the defaults for missing ones.
+func numberArgs(args []interface{}, defaults ...float64) ([]float64, error) {
+	if len(args) > len(defaults) {
+		return nil, errInvalidArguments
+	}
+
+	numbers := append([]float64{}, defaults...)
+	for idx, arg := range args {
+		number, ok := arg.(float64)
+		if !ok {
+			return nil, errInvalidArguments
+		}
+
+		numbers[idx] = number
+	}
+
+	return numbers, nil
+}
+
+// seq returns the position of the resource, starting from 1.
+func seq(_ *rand.Rand, idx int, _ []interface{}) (interface{}, error) {
+	return float64(idx + 1), nil
+}
+
+// randomInt returns an integer between min and max inclusive, by default between 0 and 100.
+func randomInt(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	numbers, err := numberArgs(args, 0, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := math.Ceil(numbers[0]), math.Floor(numbers[1])
+	if max < min {
+		return nil, errInvalidArguments
+	}
+
+	return min + float64(r.Int63n(int64(max-min)+1)), nil
+}
+
+// randomFloat returns a number between min and max with two decimals, by default between 0 and 1.
+func randomFloat(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	numbers, err := numberArgs(args, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := numbers[0], numbers[1]
+	if max < min {
+		return nil, errInvalidArguments
+	}
+
+	return math.Round((min+r.Float64()*(max-min))*100) / 100, nil
+}
+
+func randomBool(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	return r.Intn(2) == 1, nil
+}
+
+// pick returns one of the arguments, e.g. 'pick "draft" "published"'.
+func pick(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, errInvalidArguments
+	}
+
+	return args[r.Intn(len(args))], nil
+}
+
+// word returns a function, that returns one of the words.
+func word(words []string) function {
+	return func(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+		if len(args) > 0 {
+			return nil, errInvalidArguments
+		}
+
+		return words[r.Intn(len(words))], nil
+	}
+}
+
+func name(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	return firstNames[r.Intn(len(firstNames))] + " " + lastNames[r.Intn(len(lastNames))], nil
+}
+
+func username(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	first := strings.ToLower(firstNames[r.Intn(len(firstNames))])
+	last := strings.ToLower(lastNames[r.Intn(len(lastNames))])
+
+	return fmt.Sprintf("%s.%s%d", first, last, r.Intn(100)), nil
+}
+
+func email(r *rand.Rand, idx int, args []interface{}) (interface{}, error) {
+	user, err := username(r, idx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("%s@%s", user, domains[r.Intn(len(domains))]), nil
+}
+
+func phone(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	return fmt.Sprintf("+1-%03d-%03d-%04d", 200+r.Intn(800), r.Intn(1000), r.Intn(10000)), nil
+}
+
+func company(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	return companies[r.Intn(len(companies))] + " " + suffixes[r.Intn(len(suffixes))], nil
+}
+
+func street(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	return fmt.Sprintf("%d %s", 1+r.Intn(200), streets[r.Intn(len(streets))]), nil
+}
+
+// sentence returns a sentence of lorem ipsum words, by default between 4 and 12 words.
+func sentence(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	numbers, err := numberArgs(args, 4, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := int(numbers[0]), int(numbers[1])
+	if min < 1 || max < min {
+		return nil, errInvalidArguments
+	}
+
+	words := make([]string, min+r.Intn(max-min+1))
+	for idx := range words {
+		words[idx] = loremWords[r.Intn(len(loremWords))]
+	}
+
+	text := strings.Join(words, " ")
+
+	return strings.ToUpper(text[:1]) + text[1:] + ".", nil
+}
+
+// paragraph returns a paragraph of lorem ipsum sentences, by default between 3 and 6 sentences.
+func paragraph(r *rand.Rand, idx int, args []interface{}) (interface{}, error) {
+	numbers, err := numberArgs(args, 3, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := int(numbers[0]), int(numbers[1])
+	if min < 1 || max < min {
+		return nil, errInvalidArguments
+	}
+
+	sentences := make([]string, min+r.Intn(max-min+1))
+	for i := range sentences {
+		s, err := sentence(r, idx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		sentences[i] = s.(string)
+	}
+
+	return strings.Join(sentences, " "), nil
+}
+
+// date returns a date formatted as 'YYYY-MM-DD', between the first day of the from year and the last
+// day of the to year, by default between 2000 and 2030.
+func date(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	numbers, err := numberArgs(args, 2000, 2030)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Date(int(numbers[0]), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(int(numbers[1])+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if !from.Before(to) {
+		return nil, errInvalidArguments
+	}
+
+	days := int(to.Sub(from).Hours() / 24)
+
+	return from.AddDate(0, 0, r.Intn(days)).Format("2006-01-02"), nil
+}
+
+// uuid returns a random version 4 uuid, generated from the seed.
+func uuid(r *rand.Rand, _ int, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errInvalidArguments
+	}
+
+	b := make([]byte, 16)
+	r.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
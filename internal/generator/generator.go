@@ -0,0 +1,211 @@
+// Package generator provides generation of synthetic resources from templates, e.g. for seed data.
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInvalidSpec      = errors.New("invalid generator spec")
+	errUnknownFunction  = errors.New("unknown template function")
+	errInvalidArguments = errors.New("invalid template function arguments")
+)
+
+// placeholderRegex matches the placeholders of templates, e.g. '{{int 18 90}}'.
+var placeholderRegex = regexp.MustCompile(`{{\s*(.*?)\s*}}`)
+
+// argRegex matches the arguments of placeholders, which are either quoted strings or bare words.
+var argRegex = regexp.MustCompile(`"[^"]*"|\S+`)
+
+// Generator generates resources from templates. Generated values depend only on the seed and the
+// templates, so the same seed always generates the same resources.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// New returns a new generator instance, with the provided seed.
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Generate returns the resources of the spec, which maps resource keys to their templates. Templates
+// with a count generate an array of that many resources, while ones without are singular resources,
+// e.g. 'users: {count: 500, fields: {name: "{{name}}", age: "{{int 18 90}}"}}'.
+func (g *Generator) Generate(spec map[string]interface{}) (map[string]interface{}, error) {
+	content := make(map[string]interface{}, len(spec))
+
+	// Keys are generated in order, so that values are deterministic.
+	for _, key := range sortedKeys(spec) {
+		resourceSpec, ok := spec[key].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errInvalidSpec, key)
+		}
+
+		fields, ok := resourceSpec["fields"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s requires fields", errInvalidSpec, key)
+		}
+
+		countValue, ok := resourceSpec["count"]
+		if !ok {
+			resource, err := g.value(fields, 0)
+			if err != nil {
+				return nil, fmt.Errorf("%w of %s", err, key)
+			}
+
+			content[key] = resource
+			continue
+		}
+
+		count, ok := countValue.(float64)
+		if !ok || count < 0 || count != float64(int(count)) {
+			return nil, fmt.Errorf("%w: %s requires a non negative integer count", errInvalidSpec, key)
+		}
+
+		resources := make([]interface{}, 0, int(count))
+		for idx := 0; idx < int(count); idx++ {
+			resource, err := g.value(fields, idx)
+			if err != nil {
+				return nil, fmt.Errorf("%w of %s", err, key)
+			}
+
+			resources = append(resources, resource)
+		}
+
+		content[key] = resources
+	}
+
+	return content, nil
+}
+
+// value returns the value of the template, for the resource at the index. Strings are expanded, while
+// arrays and objects are expanded recursively, and any other values are kept as is.
+func (g *Generator) value(template interface{}, idx int) (interface{}, error) {
+	switch v := template.(type) {
+	case map[string]interface{}:
+		value := make(map[string]interface{}, len(v))
+		for _, field := range sortedKeys(v) {
+			fieldValue, err := g.value(v[field], idx)
+			if err != nil {
+				return nil, err
+			}
+
+			value[field] = fieldValue
+		}
+
+		return value, nil
+	case []interface{}:
+		value := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			itemValue, err := g.value(item, idx)
+			if err != nil {
+				return nil, err
+			}
+
+			value = append(value, itemValue)
+		}
+
+		return value, nil
+	case string:
+		return g.expand(v, idx)
+	default:
+		return v, nil
+	}
+}
+
+// expand returns the value of a string template. A template of a single placeholder keeps the type
+// of its value, e.g. a number for '{{int 18 90}}', while any other template is a string.
+func (g *Generator) expand(template string, idx int) (interface{}, error) {
+	matches := placeholderRegex.FindAllStringSubmatchIndex(template, -1)
+	if len(matches) == 0 {
+		return template, nil
+	}
+
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(template) {
+		return g.call(template[matches[0][2]:matches[0][3]], idx)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		value, err := g.call(template[match[2]:match[3]], idx)
+		if err != nil {
+			return nil, err
+		}
+
+		sb.WriteString(template[last:match[0]])
+		sb.WriteString(format(value))
+		last = match[1]
+	}
+	sb.WriteString(template[last:])
+
+	return sb.String(), nil
+}
+
+// call returns the value of a placeholder, which is a function name followed by its arguments,
+// e.g. 'int 18 90' or 'pick "red" "blue"'.
+func (g *Generator) call(placeholder string, idx int) (interface{}, error) {
+	tokens := argRegex.FindAllString(placeholder, -1)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: %s", errUnknownFunction, placeholder)
+	}
+
+	fn, ok := functions[tokens[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownFunction, tokens[0])
+	}
+
+	args := make([]interface{}, 0, len(tokens)-1)
+	for _, token := range tokens[1:] {
+		args = append(args, parseArg(token))
+	}
+
+	value, err := fn(g.rand, idx, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, placeholder)
+	}
+
+	return value, nil
+}
+
+// parseArg returns the value of an argument, which is a number unless it's quoted or not numeric.
+func parseArg(token string) interface{} {
+	if len(token) >= 2 && strings.HasPrefix(token, `"`) && strings.HasSuffix(token, `"`) {
+		return token[1 : len(token)-1]
+	}
+
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return number
+	}
+
+	return token
+}
+
+// format returns the string form of a value, as part of a string template.
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
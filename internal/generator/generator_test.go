@@ -0,0 +1,129 @@
+package generator_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/generator"
+)
+
+func TestGenerate(t *testing.T) {
+	spec := map[string]interface{}{
+		"users": map[string]interface{}{
+			"count": float64(50),
+			"fields": map[string]interface{}{
+				"id":     "{{seq}}",
+				"name":   "{{name}}",
+				"email":  "{{email}}",
+				"age":    "{{int 18 90}}",
+				"handle": "@{{username}}-{{seq}}",
+				"status": `{{pick "active" "banned"}}`,
+				"tags":   []interface{}{"{{word}}", "static"},
+				"active": true,
+			},
+		},
+		"settings": map[string]interface{}{
+			"fields": map[string]interface{}{"theme": `{{ pick "dark" "light" }}`},
+		},
+	}
+
+	got, err := generator.New(42).Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users, ok := got["users"].([]interface{})
+	if !ok || len(users) != 50 {
+		t.Fatalf("expected %v users, but got %v", 50, got["users"])
+	}
+
+	emailRegex := regexp.MustCompile(`^[a-z]+\.[a-z]+\d+@example\.(com|org|net)$`)
+	for idx, item := range users {
+		user := item.(map[string]interface{})
+
+		if expected := float64(idx + 1); user["id"] != expected {
+			t.Fatalf("expected id %v, but got %v", expected, user["id"])
+		}
+
+		if age, ok := user["age"].(float64); !ok || age < 18 || age > 90 || age != float64(int(age)) {
+			t.Fatalf("expected integer age between 18 and 90, but got %v", user["age"])
+		}
+
+		if email, ok := user["email"].(string); !ok || !emailRegex.MatchString(email) {
+			t.Fatalf("expected email, but got %v", user["email"])
+		}
+
+		if status := user["status"]; status != "active" && status != "banned" {
+			t.Fatalf("expected status active or banned, but got %v", status)
+		}
+
+		if tags := user["tags"].([]interface{}); len(tags) != 2 || tags[1] != "static" {
+			t.Fatalf("expected tags with static value, but got %v", tags)
+		}
+
+		if user["active"] != true {
+			t.Fatalf("expected active %v, but got %v", true, user["active"])
+		}
+	}
+
+	settings, ok := got["settings"].(map[string]interface{})
+	if !ok || (settings["theme"] != "dark" && settings["theme"] != "light") {
+		t.Fatalf("expected singular settings, but got %v", got["settings"])
+	}
+
+	// The same seed generates the same resources.
+	again, err := generator.New(42).Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, again) {
+		t.Fatalf("expected same resources for the same seed, but got %v and %v", got, again)
+	}
+
+	other, err := generator.New(43).Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reflect.DeepEqual(got, other) {
+		t.Fatalf("expected different resources for different seeds, but got %v", other)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec map[string]interface{}
+	}{
+		{
+			name: "Template not an object",
+			spec: map[string]interface{}{"users": "{{name}}"},
+		},
+		{
+			name: "Missing fields",
+			spec: map[string]interface{}{"users": map[string]interface{}{"count": float64(1)}},
+		},
+		{
+			name: "Invalid count",
+			spec: map[string]interface{}{"users": map[string]interface{}{"count": 1.5, "fields": map[string]interface{}{}}},
+		},
+		{
+			name: "Unknown function",
+			spec: map[string]interface{}{"users": map[string]interface{}{"count": float64(1), "fields": map[string]interface{}{"name": "{{unknown}}"}}},
+		},
+		{
+			name: "Invalid arguments",
+			spec: map[string]interface{}{"users": map[string]interface{}{"count": float64(1), "fields": map[string]interface{}{"age": "{{int 90 18}}"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := generator.New(1).Generate(tc.spec); err == nil {
+				t.Fatalf("expected error, but got %v", err)
+			}
+		})
+	}
+}
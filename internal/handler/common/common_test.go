@@ -20,6 +20,7 @@ var (
 	testResourceKeys    = []string{"resource_key_1", "resource_key_2"}
 	testData            = make(storage.Database)
 	testResourceStorage = make(map[string]*storage.Mock)
+	testSingularData    = map[string]storage.Resource{"profile": {"id": "1", "name": "typicode"}}
 )
 
 func TestMain(m *testing.M) {
@@ -34,7 +35,14 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	router := handler.Setup(resourceStorage)
+	singularSvc, err := storage.NewSingularMock(testSingularData, "profile")
+	if err != nil {
+		panic(err)
+	}
+
+	singularStorage := map[string]storage.SingularStorage{"profile": singularSvc}
+
+	router := handler.Setup(resourceStorage, handler.Config{Singular: singularStorage})
 
 	mockServer = httptest.NewServer(router)
 	defer mockServer.Close()
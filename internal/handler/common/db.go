@@ -9,8 +9,8 @@ import (
 	"github.com/chanioxaris/json-server/internal/web"
 )
 
-// DB operates as a http handler, to list db content.
-func DB(storageSvc storage.Storage) http.HandlerFunc {
+// DB operates as a http handler, to list db content, including any singular resources.
+func DB(storageSvc storage.Storage, singularStorage map[string]storage.SingularStorage) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		data, err := storageSvc.DB()
 		if err != nil {
@@ -18,6 +18,24 @@ func DB(storageSvc storage.Storage) http.HandlerFunc {
 			return
 		}
 
-		web.Success(w, http.StatusOK, data)
+		// Return only the requested fields of every resource.
+		fields := web.QueryList(r.URL.Query(), "_fields")
+
+		content := make(map[string]interface{}, len(data)+len(singularStorage))
+		for key, resources := range data {
+			content[key] = storage.ProjectAll(resources, fields)
+		}
+
+		for key, singularSvc := range singularStorage {
+			resource, err := singularSvc.Get()
+			if err != nil {
+				web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+				return
+			}
+
+			content[key] = storage.Project(resource, fields)
+		}
+
+		web.Success(w, http.StatusOK, content)
 	}
 }
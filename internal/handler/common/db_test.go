@@ -15,19 +15,26 @@ func TestDB(t *testing.T) {
 	testCases := []struct {
 		name         string
 		statusCode   int
-		expectedData storage.Database
+		query        string
+		expectedData map[string]interface{}
 	}{
 		{
 			name:         "Get db",
 			statusCode:   http.StatusOK,
-			expectedData: testData,
+			expectedData: testProjectData(),
+		},
+		{
+			name:         "Get db with fields",
+			statusCode:   http.StatusOK,
+			query:        "_fields=id,field_1",
+			expectedData: testProjectData("id", "field_1"),
 		},
 	}
 
 	for _, tt := range testCases {
 		testResetData("db")
 
-		url := fmt.Sprintf("%s/db", mockServer.URL)
+		url := fmt.Sprintf("%s/db?%s", mockServer.URL, tt.query)
 
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
@@ -58,3 +65,36 @@ func TestDB(t *testing.T) {
 		}
 	}
 }
+
+func testProjectData(fields ...string) map[string]interface{} {
+	project := func(resource storage.Resource) storage.Resource {
+		if len(fields) == 0 {
+			return resource
+		}
+
+		newResource := storage.Resource{}
+		for _, field := range fields {
+			if val, ok := resource[field]; ok {
+				newResource[field] = val
+			}
+		}
+
+		return newResource
+	}
+
+	projected := make(map[string]interface{})
+	for key, resources := range testData {
+		projectedResources := make([]storage.Resource, 0)
+		for _, resource := range resources {
+			projectedResources = append(projectedResources, project(resource))
+		}
+
+		projected[key] = projectedResources
+	}
+
+	for key, resource := range testSingularData {
+		projected[key] = project(resource)
+	}
+
+	return projected
+}
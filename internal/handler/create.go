@@ -10,7 +10,7 @@ import (
 )
 
 // Create operates as a http handler, to add a new resource.
-func Create(storageSvc storage.Storage) http.HandlerFunc {
+func Create(storageSvc storage.Storage, parent *Parent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Read and decode request body.
 		var newResource storage.Resource
@@ -20,11 +20,27 @@ func Create(storageSvc storage.Storage) http.HandlerFunc {
 		}
 
 		// Check if request body is empty, or contains only id.
-		if _, ok := newResource["id"]; len(newResource) == 0 || (len(newResource) == 1 && ok) {
+		if _, ok := newResource[storageSvc.IDField()]; len(newResource) == 0 || (len(newResource) == 1 && ok) {
 			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
 			return
 		}
 
+		// Resolve the parent resource of nested routes.
+		parentResource, err := parent.resolve(r, storageSvc)
+		if err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		// Refer to the parent resource of nested routes.
+		parent.assign(newResource, parentResource)
+
 		// Create the new resource.
 		data, err := storageSvc.Create(newResource)
 		if err != nil {
@@ -34,6 +50,12 @@ func Create(storageSvc storage.Storage) http.HandlerFunc {
 				return
 			}
 
+			// Invalid resource for the storage.
+			if errors.Is(err, storage.ErrBadRequest) {
+				web.Error(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
 			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
 			return
 		}
@@ -132,3 +132,83 @@ func TestCreate(t *testing.T) {
 		}
 	}
 }
+
+func TestCreate_IDField(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         storage.Resource
+		statusCode   int
+		expectedData storage.Resource
+	}{
+		{
+			name:         "Read resource by custom id field",
+			method:       http.MethodGet,
+			path:         "/products/abc-1",
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"sku": "abc-1", "name": "keyboard"},
+		},
+		{
+			name:         "Update resource by numeric custom id field",
+			method:       http.MethodPatch,
+			path:         "/products/2",
+			body:         storage.Resource{"sku": "random", "name": "trackball"},
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"sku": float64(2), "name": "trackball"},
+		},
+		{
+			name:         "Create resource with generated custom id field",
+			method:       http.MethodPost,
+			path:         "/products",
+			body:         storage.Resource{"name": "monitor"},
+			statusCode:   http.StatusCreated,
+			expectedData: storage.Resource{"sku": "3", "name": "monitor"},
+		},
+		{
+			name:       "Create resource with only custom id field",
+			method:     http.MethodPost,
+			path:       "/products",
+			body:       storage.Resource{"sku": "abc-4"},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		url := fmt.Sprintf("%s%s", mockServer.URL, tt.path)
+
+		var reqBody bytes.Buffer
+		if tt.body != nil {
+			if err := json.NewEncoder(&reqBody).Encode(tt.body); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		req, err := http.NewRequest(tt.method, url, &reqBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf("%s: expected status code %v, but got %v", tt.name, tt.statusCode, resp.StatusCode)
+		}
+
+		if tt.expectedData == nil {
+			continue
+		}
+
+		var body storage.Resource
+		if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(body, tt.expectedData) {
+			t.Fatalf("%s: expected body %v, but got %v", tt.name, tt.expectedData, body)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+const (
+	paramCursor   = "_cursor"
+	paramEnvelope = "_envelope"
+
+	headerNextCursor = "X-Next-Cursor"
+)
+
+// cursorPagination represents a keyset pagination, where each page starts right after
+// the last resource of the previous one. That keeps pages stable, even if resources are
+// created or deleted between requests.
+type cursorPagination struct {
+	fields   []storage.SortField
+	keys     []storage.SortKey
+	limit    int
+	envelope bool
+}
+
+// cursorToken represents the contents of an opaque cursor.
+type cursorToken struct {
+	// Sort describes the sort fields the cursor was created for.
+	Sort string `json:"s"`
+	// Keys are the sort keys of the last resource in page.
+	Keys []storage.SortKey `json:"k"`
+}
+
+// cursorEnvelope represents the response body of a cursor page, when enveloped.
+type cursorEnvelope struct {
+	Data       []storage.Resource `json:"data"`
+	NextCursor *string            `json:"next_cursor"`
+}
+
+// parseCursorPagination builds the cursor pagination from the query parameters and the sort
+// fields of the request. Returns nil if no cursor is provided. An empty cursor requests the first page.
+func parseCursorPagination(query url.Values, sortFields []storage.SortField, idField string) (*cursorPagination, error) {
+	if _, ok := query[paramCursor]; !ok {
+		return nil, nil
+	}
+
+	// Cursors can't be combined with offset pagination.
+	for _, param := range []string{paramPage, paramStart, paramEnd} {
+		if query.Get(param) != "" {
+			return nil, fmt.Errorf("%w: %s can't be combined with %s", storage.ErrBadRequest, paramCursor, param)
+		}
+	}
+
+	limit, hasLimit, err := parseIntParam(query, paramLimit, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasLimit {
+		limit = defaultPageLimit
+	}
+
+	envelope, err := strconv.ParseBool(query.Get(paramEnvelope))
+	if err != nil && query.Get(paramEnvelope) != "" {
+		return nil, fmt.Errorf("%w: invalid %s", storage.ErrBadRequest, paramEnvelope)
+	}
+
+	p := &cursorPagination{
+		fields:   cursorSortFields(sortFields, idField),
+		limit:    limit,
+		envelope: envelope,
+	}
+
+	if token := query.Get(paramCursor); token != "" {
+		if p.keys, err = p.decode(token); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// cursorSortFields appends the id field to the sort fields, in order to break ties between resources.
+func cursorSortFields(sortFields []storage.SortField, idField string) []storage.SortField {
+	fields := make([]storage.SortField, 0, len(sortFields)+1)
+	for _, field := range sortFields {
+		fields = append(fields, field)
+
+		if field.Field == idField {
+			return fields
+		}
+	}
+
+	return append(fields, storage.SortField{Field: idField})
+}
+
+// page returns the resources of the requested page from the sorted collection, along with the
+// cursor of the next page. The next cursor is empty if there are no more resources.
+func (p *cursorPagination) page(sorted []storage.Resource) ([]storage.Resource, string, error) {
+	remaining := sorted
+	if p.keys != nil {
+		remaining = storage.SortAfter(sorted, p.fields, p.keys)
+	}
+
+	if len(remaining) <= p.limit {
+		return remaining, "", nil
+	}
+
+	page := remaining[:p.limit]
+
+	next, err := p.encode(page[len(page)-1])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return page, next, nil
+}
+
+// response returns the response body and headers of a page.
+func (p *cursorPagination) response(page []storage.Resource, next string) (interface{}, http.Header) {
+	if p.envelope {
+		envelope := cursorEnvelope{Data: page}
+		if next != "" {
+			envelope.NextCursor = &next
+		}
+
+		return envelope, nil
+	}
+
+	headers := http.Header{}
+	if next != "" {
+		headers.Set(headerNextCursor, next)
+	}
+
+	return page, headers
+}
+
+// encode the position of resource to an opaque cursor.
+func (p *cursorPagination) encode(resource storage.Resource) (string, error) {
+	token := cursorToken{Sort: p.describe(), Keys: storage.SortKeys(resource, p.fields)}
+
+	tokenBytes, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// decode an opaque cursor to sort keys. The cursor must be created for the same sort fields.
+func (p *cursorPagination) decode(cursor string) ([]storage.SortKey, error) {
+	tokenBytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", storage.ErrBadRequest)
+	}
+
+	var token cursorToken
+	if err = json.Unmarshal(tokenBytes, &token); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", storage.ErrBadRequest)
+	}
+
+	if token.Sort != p.describe() || len(token.Keys) != len(p.fields) {
+		return nil, fmt.Errorf("%w: cursor does not match sort fields", storage.ErrBadRequest)
+	}
+
+	return token.Keys, nil
+}
+
+// describe returns a textual representation of the sort fields, e.g. 'published:desc,id:asc'.
+func (p *cursorPagination) describe() string {
+	fields := make([]string, 0, len(p.fields))
+	for _, field := range p.fields {
+		order := "asc"
+		if field.Descending {
+			order = "desc"
+		}
+
+		fields = append(fields, field.Field+":"+order)
+	}
+
+	return strings.Join(fields, ",")
+}
@@ -11,11 +11,23 @@ import (
 )
 
 // Delete operates as a http handler, to delete an existing resource.
-func Delete(storageSvc storage.Storage) http.HandlerFunc {
+func Delete(storageSvc storage.Storage, parent *Parent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Read request path parameter id.
 		id := mux.Vars(r)["id"]
 
+		// Resolve the parent resource of nested routes.
+		if _, err := parent.resolve(r, storageSvc); err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
 		// Delete resource.
 		if err := storageSvc.Delete(id); err != nil {
 			// Resource not found.
@@ -9,12 +9,29 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/chanioxaris/json-server/internal/handler/common"
+	"github.com/chanioxaris/json-server/internal/inflection"
 	"github.com/chanioxaris/json-server/internal/storage"
 	"github.com/chanioxaris/json-server/internal/web/middleware"
 )
 
+// Config represents the optional settings of the API handler.
+type Config struct {
+	// Inflector converts resource names between singular and plural forms, to resolve
+	// relationships between resources. Defaults to english pluralization rules.
+	Inflector *inflection.Inflector
+	// Singular contains the storage of singular resources, e.g. a 'profile' object, by resource key.
+	Singular map[string]storage.SingularStorage
+	// Parents contains the keys of the resources that resources refer to, by resource key, to register
+	// nested routes under them. Resources without parents are nested under every other resource.
+	Parents map[string][]string
+}
+
 // Setup API handler based on provided resources.
-func Setup(resourceStorage map[string]storage.Storage) http.Handler {
+func Setup(resourceStorage map[string]storage.Storage, cfg Config) http.Handler {
+	if cfg.Inflector == nil {
+		cfg.Inflector = inflection.New(nil)
+	}
+
 	router := mux.NewRouter().StrictSlash(true)
 	router.Use(middleware.Recovery)
 	router.Use(middleware.Logger)
@@ -23,17 +40,31 @@ func Setup(resourceStorage map[string]storage.Storage) http.Handler {
 	for resourceKey, storageSvc := range resourceStorage {
 		// Common endpoint to retrieve db contents.
 		if resourceKey == "db" {
-			router.HandleFunc("/db", common.DB(storageSvc)).Methods(http.MethodGet)
+			router.HandleFunc("/db", common.DB(storageSvc, cfg.Singular)).Methods(http.MethodGet)
 			continue
 		}
 
+		relations := NewRelations(resourceKey, resourceStorage, cfg.Inflector)
+
 		// Register all default endpoint handlers for resource.
-		router.HandleFunc(fmt.Sprintf("/%s", resourceKey), List(storageSvc)).Methods(http.MethodGet)
-		router.HandleFunc(fmt.Sprintf("/%s/{id}", resourceKey), Read(storageSvc)).Methods(http.MethodGet)
-		router.HandleFunc(fmt.Sprintf("/%s", resourceKey), Create(storageSvc)).Methods(http.MethodPost)
-		router.HandleFunc(fmt.Sprintf("/%s/{id}", resourceKey), Replace(storageSvc)).Methods(http.MethodPut)
-		router.HandleFunc(fmt.Sprintf("/%s/{id}", resourceKey), Update(storageSvc)).Methods(http.MethodPatch)
-		router.HandleFunc(fmt.Sprintf("/%s/{id}", resourceKey), Delete(storageSvc)).Methods(http.MethodDelete)
+		registerRoutes(router, fmt.Sprintf("/%s", resourceKey), storageSvc, relations, nil)
+
+		// Register nested endpoint handlers for resource, as a child of every resource it refers to.
+		for _, parentKey := range parentKeys(resourceKey, resourceStorage, cfg.Parents) {
+			parentStorage := resourceStorage[parentKey]
+
+			path := fmt.Sprintf("/%s/{parentId}/%s", parentKey, resourceKey)
+			parent := NewParent(parentStorage, relations.foreignKey(parentKey))
+
+			registerRoutes(router, path, storageSvc, relations, parent)
+		}
+	}
+
+	// For each singular resource create the appropriate endpoint handlers.
+	for resourceKey, singularSvc := range cfg.Singular {
+		router.HandleFunc(fmt.Sprintf("/%s", resourceKey), ReadSingular(singularSvc)).Methods(http.MethodGet)
+		router.HandleFunc(fmt.Sprintf("/%s", resourceKey), ReplaceSingular(singularSvc)).Methods(http.MethodPut)
+		router.HandleFunc(fmt.Sprintf("/%s", resourceKey), UpdateSingular(singularSvc)).Methods(http.MethodPatch)
 	}
 
 	// Render a home page with useful info.
@@ -41,3 +72,36 @@ func Setup(resourceStorage map[string]storage.Storage) http.Handler {
 
 	return router
 }
+
+// parentKeys returns the keys of the resources that the resource key is nested under, which are its
+// configured parents, or every other resource by default.
+func parentKeys(key string, resourceStorage map[string]storage.Storage, parents map[string][]string) []string {
+	keys, ok := parents[key]
+	if !ok {
+		keys = make([]string, 0, len(resourceStorage))
+		for parentKey := range resourceStorage {
+			keys = append(keys, parentKey)
+		}
+	}
+
+	valid := make([]string, 0, len(keys))
+	for _, parentKey := range keys {
+		if _, ok := resourceStorage[parentKey]; !ok || parentKey == "db" || parentKey == key {
+			continue
+		}
+
+		valid = append(valid, parentKey)
+	}
+
+	return valid
+}
+
+// registerRoutes registers the endpoint handlers of a resource collection under path.
+func registerRoutes(router *mux.Router, path string, storageSvc storage.Storage, relations *Relations, parent *Parent) {
+	router.HandleFunc(path, List(storageSvc, relations, parent)).Methods(http.MethodGet)
+	router.HandleFunc(path+"/{id}", Read(storageSvc, relations, parent)).Methods(http.MethodGet)
+	router.HandleFunc(path, Create(storageSvc, parent)).Methods(http.MethodPost)
+	router.HandleFunc(path+"/{id}", Replace(storageSvc, parent)).Methods(http.MethodPut)
+	router.HandleFunc(path+"/{id}", Update(storageSvc, parent)).Methods(http.MethodPatch)
+	router.HandleFunc(path+"/{id}", Delete(storageSvc, parent)).Methods(http.MethodDelete)
+}
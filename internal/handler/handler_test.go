@@ -22,6 +22,55 @@ var (
 	testResourceKeys    = []string{"resource_key_1", "resource_key_2"}
 	testData            = make(storage.Database)
 	testResourceStorage = make(map[string]*storage.Mock)
+
+	// testSingularData contains singular resources.
+	testSingularData = map[string]storage.Resource{
+		"profile": {"name": "typicode", "age": float64(30)},
+	}
+
+	// testProductData contains resources with a custom id field.
+	testProductData = storage.Database{
+		"products": {
+			{"sku": "abc-1", "name": "keyboard"},
+			{"sku": float64(2), "name": "mouse"},
+		},
+	}
+
+	// testRelationData contains resources that refer to each other through foreign keys.
+	testRelationData = storage.Database{
+		"posts": {
+			{"id": "1", "title": "json-server"},
+			{"id": "2", "title": "json-server in go"},
+		},
+		"comments": {
+			{"id": "1", "body": "first comment", "postId": "1"},
+			{"id": "2", "body": "second comment", "postId": float64(1)},
+			{"id": "3", "body": "third comment", "postId": "2"},
+			{"id": "4", "body": "orphan comment"},
+		},
+		"albums": {
+			{"id": "1", "title": "holidays"},
+			{"id": "2", "title": "birthdays"},
+		},
+		"photos": {
+			{"id": "1", "title": "beach", "albumId": "1"},
+			{"id": "2", "title": "cake", "albumId": "2"},
+		},
+		"replies": {},
+		"people": {
+			{"id": "1", "name": "typicode"},
+		},
+		"pets": {
+			{"id": "1", "name": "rex", "personId": "1"},
+		},
+	}
+
+	// testParents contains the resources that resources are nested under, while the rest are nested
+	// under every other resource.
+	testParents = map[string][]string{
+		"comments": {"posts"},
+		"photos":   {"albums"},
+	}
 )
 
 func TestMain(m *testing.M) {
@@ -37,7 +86,17 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	router := handler.Setup(resourceStorage)
+	singularStorage := make(map[string]storage.SingularStorage)
+	for key := range testSingularData {
+		singularSvc, err := storage.NewSingularMock(testSingularData, key)
+		if err != nil {
+			panic(err)
+		}
+
+		singularStorage[key] = singularSvc
+	}
+
+	router := handler.Setup(resourceStorage, handler.Config{Singular: singularStorage, Parents: testParents})
 
 	mockServer = httptest.NewServer(router)
 	defer mockServer.Close()
@@ -79,6 +138,24 @@ func testCreateResourceStorage(resourceKeys []string) (map[string]storage.Storag
 		resourceStorage[resourceKey] = storageSvc
 	}
 
+	for key := range testRelationData {
+		storageSvc, err := storage.NewMock(testRelationData, key)
+		if err != nil {
+			return nil, errors.New("failed to initialize resources")
+		}
+
+		resourceStorage[key] = storageSvc
+	}
+
+	for key := range testProductData {
+		storageSvc, err := storage.NewMock(testProductData, key, storage.WithIDField("sku"))
+		if err != nil {
+			return nil, errors.New("failed to initialize resources")
+		}
+
+		resourceStorage[key] = storageSvc
+	}
+
 	for key, storageSvc := range resourceStorage {
 		testResourceStorage[key] = storageSvc.(*storage.Mock)
 	}
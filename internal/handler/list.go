@@ -1,22 +1,89 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/chanioxaris/json-server/internal/storage"
 	"github.com/chanioxaris/json-server/internal/web"
 )
 
-// List operates as a http handler, to return all available resources.
-func List(storageSvc storage.Storage) http.HandlerFunc {
+// List operates as a http handler, to return all available resources that match the query parameters.
+func List(storageSvc storage.Storage, relations *Relations, parent *Parent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse query parameters.
+		query, err := parseListQuery(r.URL.Query(), storageSvc.IDField())
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
+			return
+		}
+
+		include, err := relations.parse(r.URL.Query())
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
+			return
+		}
+
+		// Resolve the parent resource of nested routes.
+		parentResource, err := parent.resolve(r, storageSvc)
+		if err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
 		// Find all resources.
-		data, err := storageSvc.Find()
+		data, err := storageSvc.Find(append(parent.filter(parentResource), query.filter...))
+		if err != nil {
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		// Keep only the requested page.
+		page, next, err := query.paginate(data)
 		if err != nil {
 			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
 			return
 		}
 
-		web.Success(w, http.StatusOK, data)
+		// Include any requested relationships.
+		page, err = relations.include(page, include)
+		if err != nil {
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		page = storage.ProjectAll(page, query.fields)
+
+		switch {
+		case query.cursor != nil:
+			body, headers := query.cursor.response(page, next)
+			web.SuccessWithHeaders(w, http.StatusOK, body, headers)
+		case query.paging != nil:
+			web.SuccessWithHeaders(w, http.StatusOK, page, query.paging.headers(r, len(data)))
+		default:
+			web.Success(w, http.StatusOK, page)
+		}
+	}
+}
+
+// paginate returns the requested page of the sorted resources. For cursor pagination, the
+// cursor of the next page is also returned.
+func (q *listQuery) paginate(resources []storage.Resource) ([]storage.Resource, string, error) {
+	switch {
+	case q.cursor != nil:
+		return q.cursor.page(storage.Sort(resources, q.cursor.fields))
+	case q.paging != nil:
+		sorted := q.sortResources(resources)
+		start, end := q.paging.bounds(len(sorted))
+
+		return sorted[start:end], "", nil
+	default:
+		return q.sortResources(resources), "", nil
 	}
 }
@@ -6,6 +6,9 @@ import (
 	"math/rand"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/chanioxaris/json-server/internal/storage"
@@ -15,11 +18,26 @@ func TestList(t *testing.T) {
 	randomKeyIndex := rand.Intn(len(testResourceKeys))
 	randomKey := testResourceKeys[randomKeyIndex]
 
+	// Previous tests may have deleted all resources of the random key.
+	if len(testData[randomKey]) == 0 {
+		randomKey = testResourceKeys[(randomKeyIndex+1)%len(testResourceKeys)]
+	}
+
+	type bodyError struct {
+		Error string `json:"error"`
+	}
+
+	firstResource := testData[randomKey][0]
+	lastResource := testData[randomKey][len(testData[randomKey])-1]
+
 	testCases := []struct {
 		name         string
 		statusCode   int
 		key          string
+		query        string
 		expectedData interface{}
+		wantErr      bool
+		err          error
 	}{
 		{
 			name:         "List resources",
@@ -27,12 +45,272 @@ func TestList(t *testing.T) {
 			key:          randomKey,
 			expectedData: testData[randomKey],
 		},
+		{
+			name:       "List resources filtered by field",
+			statusCode: http.StatusOK,
+			key:        randomKey,
+			query:      fmt.Sprintf("field_1=%s", firstResource["field_1"]),
+			expectedData: testFilterResources(randomKey, func(r storage.Resource) bool {
+				return r["field_1"] == firstResource["field_1"]
+			}),
+		},
+		{
+			name:         "List resources filtered by multiple fields",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        fmt.Sprintf("id=%s&field_2=%s", lastResource["id"], lastResource["field_2"]),
+			expectedData: []storage.Resource{lastResource},
+		},
+		{
+			name:       "List resources filtered by repeated field",
+			statusCode: http.StatusOK,
+			key:        randomKey,
+			query:      fmt.Sprintf("id=%s&id=%s", firstResource["id"], lastResource["id"]),
+			expectedData: testFilterResources(randomKey, func(r storage.Resource) bool {
+				return r["id"] == firstResource["id"] || r["id"] == lastResource["id"]
+			}),
+		},
+		{
+			name:         "List resources filtered by non matching field",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        "field_1=randomValue",
+			expectedData: []storage.Resource{},
+		},
+		{
+			name:       "List resources filtered by not equal field",
+			statusCode: http.StatusOK,
+			key:        randomKey,
+			query:      fmt.Sprintf("id_ne=%s", firstResource["id"]),
+			expectedData: testFilterResources(randomKey, func(r storage.Resource) bool {
+				return r["id"] != firstResource["id"]
+			}),
+		},
+		{
+			name:       "List resources filtered by range",
+			statusCode: http.StatusOK,
+			key:        randomKey,
+			query:      "id_gte=2&id_lte=5",
+			expectedData: testFilterResources(randomKey, func(r storage.Resource) bool {
+				return r["id"].(string) >= "2" && r["id"].(string) <= "5"
+			}),
+		},
+		{
+			name:       "List resources filtered by pattern",
+			statusCode: http.StatusOK,
+			key:        randomKey,
+			query:      "field_1_like=^FIELD_1-",
+			expectedData: testFilterResources(randomKey, func(r storage.Resource) bool {
+				return strings.HasPrefix(r["field_1"].(string), "field_1-")
+			}),
+		},
+		{
+			name:         "List resources sorted by field",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        "_sort=field_2&_order=desc",
+			expectedData: testSortResources(randomKey, "field_2", true),
+		},
+		{
+			name:         "List filtered resources sorted by field",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        "_sort=field_1&field_1_like=^field_1-",
+			expectedData: testSortResources(randomKey, "field_1", false, "field_1-"),
+		},
+		{
+			name:         "List resources by search",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        "q=FIELD_2-",
+			expectedData: testSearchResources(randomKey, false, "field_2-"),
+		},
+		{
+			name:         "List resources by tokenized search",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        "q=new%20field_1-&_tokenize=true",
+			expectedData: testSearchResources(randomKey, true, "new", "field_1-"),
+		},
+		{
+			name:         "List resources with fields",
+			statusCode:   http.StatusOK,
+			key:          randomKey,
+			query:        "_fields=id&_fields=field_2",
+			expectedData: storage.ProjectAll(testData[randomKey], []string{"id", "field_2"}),
+		},
+		{
+			name:       "List resources sorted by invalid order",
+			statusCode: http.StatusBadRequest,
+			key:        randomKey,
+			query:      "_sort=field_1&_order=random",
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+		{
+			name:       "List resources filtered by invalid pattern",
+			statusCode: http.StatusBadRequest,
+			key:        randomKey,
+			query:      "field_1_like=[",
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
 	}
 
 	for _, tt := range testCases {
 		testResetData(tt.key)
 
-		url := fmt.Sprintf("%s/%s", mockServer.URL, tt.key)
+		url := fmt.Sprintf("%s/%s?%s", mockServer.URL, tt.key, tt.query)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf("expected status code %v, but got %v", tt.statusCode, resp.StatusCode)
+		}
+
+		if !tt.wantErr {
+			var body []storage.Resource
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(body, tt.expectedData) {
+				t.Fatalf("expected body %v, but got %v", tt.expectedData, body)
+			}
+		} else {
+			var body bodyError
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Error != tt.err.Error() {
+				t.Fatalf("expected error message %v, but got %v", tt.err, body.Error)
+			}
+		}
+	}
+}
+
+func testFilterResources(key string, match func(storage.Resource) bool) []storage.Resource {
+	filtered := make([]storage.Resource, 0)
+	for _, resource := range testData[key] {
+		if match(resource) {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	return filtered
+}
+
+func testSortResources(key, field string, descending bool, prefix ...string) []storage.Resource {
+	sorted := testFilterResources(key, func(r storage.Resource) bool {
+		return len(prefix) == 0 || strings.HasPrefix(r[field].(string), prefix[0])
+	})
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i][field].(string) > sorted[j][field].(string)
+		}
+
+		return sorted[i][field].(string) < sorted[j][field].(string)
+	})
+
+	return sorted
+}
+
+func TestList_Pagination(t *testing.T) {
+	randomKeyIndex := rand.Intn(len(testResourceKeys))
+	randomKey := testResourceKeys[randomKeyIndex]
+
+	// Previous tests may have deleted all resources of the random key.
+	if len(testData[randomKey]) == 0 {
+		randomKey = testResourceKeys[(randomKeyIndex+1)%len(testResourceKeys)]
+	}
+
+	type bodyError struct {
+		Error string `json:"error"`
+	}
+
+	resources := testData[randomKey]
+	total := len(resources)
+	baseURL := fmt.Sprintf("%s/%s", mockServer.URL, randomKey)
+
+	testCases := []struct {
+		name         string
+		statusCode   int
+		query        string
+		expectedData []storage.Resource
+		expectedLink string
+		wantErr      bool
+		err          error
+	}{
+		{
+			name:         "List first page of resources",
+			statusCode:   http.StatusOK,
+			query:        "_page=1&_limit=1",
+			expectedData: resources[:1],
+			expectedLink: testPaginationLink(baseURL, 1, 1, total),
+		},
+		{
+			name:         "List last page of resources",
+			statusCode:   http.StatusOK,
+			query:        fmt.Sprintf("_page=%d&_limit=1", total),
+			expectedData: resources[total-1:],
+			expectedLink: testPaginationLink(baseURL, total, 1, total),
+		},
+		{
+			name:         "List page of resources out of range",
+			statusCode:   http.StatusOK,
+			query:        fmt.Sprintf("_page=%d&_limit=1", total+1),
+			expectedData: []storage.Resource{},
+			expectedLink: testPaginationLink(baseURL, total+1, 1, total),
+		},
+		{
+			name:         "List page of resources with default limit",
+			statusCode:   http.StatusOK,
+			query:        "_page=1",
+			expectedData: resources[:testMin(10, total)],
+			expectedLink: testPaginationLink(baseURL, 1, 10, total),
+		},
+		{
+			name:         "List slice of resources",
+			statusCode:   http.StatusOK,
+			query:        "_start=0&_end=2",
+			expectedData: resources[:testMin(2, total)],
+		},
+		{
+			name:         "List slice of resources with limit",
+			statusCode:   http.StatusOK,
+			query:        "_start=1&_limit=1",
+			expectedData: resources[testMin(1, total):testMin(2, total)],
+		},
+		{
+			name:       "List page of resources with invalid page",
+			statusCode: http.StatusBadRequest,
+			query:      "_page=0",
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+		{
+			name:       "List slice of resources with invalid range",
+			statusCode: http.StatusBadRequest,
+			query:      "_start=2&_end=1",
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		testResetData(randomKey)
+
+		url := fmt.Sprintf("%s?%s", baseURL, tt.query)
 
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
@@ -48,13 +326,213 @@ func TestList(t *testing.T) {
 			t.Fatalf("expected status code %v, but got %v", tt.statusCode, resp.StatusCode)
 		}
 
-		var body []storage.Resource
-		if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if !tt.wantErr {
+			if header := resp.Header.Get("X-Total-Count"); header != strconv.Itoa(total) {
+				t.Fatalf("expected header X-Total-Count %v, but got %v", total, header)
+			}
+
+			if header := resp.Header.Get("Link"); header != tt.expectedLink {
+				t.Fatalf("expected header Link %v, but got %v", tt.expectedLink, header)
+			}
+
+			var body []storage.Resource
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(body, tt.expectedData) {
+				t.Fatalf("expected body %v, but got %v", tt.expectedData, body)
+			}
+		} else {
+			var body bodyError
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Error != tt.err.Error() {
+				t.Fatalf("expected error message %v, but got %v", tt.err, body.Error)
+			}
+		}
+	}
+}
+
+func testPaginationLink(baseURL string, page, limit, total int) string {
+	lastPage := (total + limit - 1) / limit
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	link := func(page int, rel string) string {
+		return fmt.Sprintf("<%s?_limit=%d&_page=%d>; rel=\"%s\"", baseURL, limit, page, rel)
+	}
+
+	links := []string{link(1, "first")}
+	if page > 1 {
+		links = append(links, link(testMin(page-1, lastPage), "prev"))
+	}
+
+	if page < lastPage {
+		links = append(links, link(page+1, "next"))
+	}
+
+	links = append(links, link(lastPage, "last"))
+
+	return strings.Join(links, ", ")
+}
+
+func testMin(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func TestList_Cursor(t *testing.T) {
+	randomKeyIndex := rand.Intn(len(testResourceKeys))
+	randomKey := testResourceKeys[randomKeyIndex]
+
+	// Previous tests may have deleted all resources of the random key.
+	if len(testData[randomKey]) == 0 {
+		randomKey = testResourceKeys[(randomKeyIndex+1)%len(testResourceKeys)]
+	}
+
+	testResetData(randomKey)
+
+	baseURL := fmt.Sprintf("%s/%s", mockServer.URL, randomKey)
+
+	// Walk through all pages following the next cursor.
+	expectedData := storage.Sort(testData[randomKey], []storage.SortField{
+		{Field: "field_1", Descending: true},
+		{Field: "id"},
+	})
+
+	got := make([]storage.Resource, 0)
+	cursor := ""
+	for {
+		var page []storage.Resource
+		resp := testListCursorPage(t, fmt.Sprintf("%s?_sort=field_1&_order=desc&_limit=2&_cursor=%s", baseURL, cursor), &page)
+
+		got = append(got, page...)
+
+		if cursor = resp.Header.Get("X-Next-Cursor"); cursor == "" {
+			break
+		}
+
+		if len(got) > len(expectedData) {
+			t.Fatalf("expected at most %d resources, but got more", len(expectedData))
+		}
+	}
+
+	if !reflect.DeepEqual(got, expectedData) {
+		t.Fatalf("expected body %v, but got %v", expectedData, got)
+	}
+
+	// Request the first page in an envelope.
+	var envelope struct {
+		Data       []storage.Resource `json:"data"`
+		NextCursor *string            `json:"next_cursor"`
+	}
+	testListCursorPage(t, fmt.Sprintf("%s?_limit=1&_cursor=&_envelope=true", baseURL), &envelope)
+
+	firstByID := storage.Sort(testData[randomKey], []storage.SortField{{Field: "id"}})
+	if !reflect.DeepEqual(envelope.Data, firstByID[:1]) {
+		t.Fatalf("expected body %v, but got %v", firstByID[:1], envelope.Data)
+	}
+
+	if (envelope.NextCursor != nil) != (len(firstByID) > 1) {
+		t.Fatalf("expected next cursor only if more resources exist, but got %v", envelope.NextCursor)
+	}
+
+	// Cursor remains stable when a resource is created before it.
+	if envelope.NextCursor != nil {
+		if _, err := testResourceStorage[randomKey].Create(storage.Resource{"id": "!", "field_1": "inserted"}); err != nil {
+			t.Fatal(err)
+		}
+
+		var page []storage.Resource
+		testListCursorPage(t, fmt.Sprintf("%s?_limit=1&_cursor=%s", baseURL, *envelope.NextCursor), &page)
+
+		if err := testResourceStorage[randomKey].Delete("!"); err != nil {
 			t.Fatal(err)
 		}
 
-		if !reflect.DeepEqual(body, tt.expectedData) {
-			t.Fatalf("expected body %v, but got %v", tt.expectedData, body)
+		if !reflect.DeepEqual(page, firstByID[1:2]) {
+			t.Fatalf("expected body %v, but got %v", firstByID[1:2], page)
 		}
 	}
+
+	// Ranked search results are paged by cursor, only if explicitly sorted.
+	var ranked []storage.Resource
+	testListCursorPage(t, fmt.Sprintf("%s?q=field_1&_tokenize=true&_sort=id&_cursor=&_limit=1", baseURL), &ranked)
+
+	if !reflect.DeepEqual(ranked, firstByID[:1]) {
+		t.Fatalf("expected body %v, but got %v", firstByID[:1], ranked)
+	}
+
+	// Invalid cursors are rejected.
+	invalidURLs := []string{
+		fmt.Sprintf("%s?_cursor=randomCursor", baseURL),
+		fmt.Sprintf("%s?_cursor=&_page=1", baseURL),
+		fmt.Sprintf("%s?q=field_1&_tokenize=true&_cursor=", baseURL),
+	}
+
+	if envelope.NextCursor != nil {
+		invalidURLs = append(invalidURLs, fmt.Sprintf("%s?_sort=field_2&_cursor=%s", baseURL, *envelope.NextCursor))
+	}
+
+	for _, url := range invalidURLs {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected status code %v, but got %v", http.StatusBadRequest, resp.StatusCode)
+		}
+	}
+}
+
+func testListCursorPage(t *testing.T, url string, body interface{}) *http.Response {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status code %v, but got %v", http.StatusOK, resp.StatusCode)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(body); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp
+}
+
+func testSearchResources(key string, rank bool, terms ...string) []storage.Resource {
+	hits := func(r storage.Resource) int {
+		count := 0
+		for _, value := range r {
+			for _, term := range terms {
+				count += strings.Count(strings.ToLower(value.(string)), term)
+			}
+		}
+
+		return count
+	}
+
+	found := testFilterResources(key, func(r storage.Resource) bool {
+		return hits(r) > 0
+	})
+
+	if rank {
+		sort.SliceStable(found, func(i, j int) bool {
+			return hits(found[i]) > hits(found[j])
+		})
+	}
+
+	return found
 }
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+const (
+	paramPage  = "_page"
+	paramLimit = "_limit"
+	paramStart = "_start"
+	paramEnd   = "_end"
+
+	defaultPageLimit = 10
+)
+
+// pagination represents the requested slice of a collection. It operates either on pages,
+// when '_page' is provided, or on offsets using '_start', '_end' and '_limit'.
+type pagination struct {
+	paged bool
+	page  int
+	limit int
+	start int
+	end   int
+}
+
+// parsePagination builds the pagination from the query parameters. Returns nil if
+// no pagination parameter is provided.
+func parsePagination(query url.Values) (*pagination, error) {
+	page, hasPage, err := parseIntParam(query, paramPage, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, hasLimit, err := parseIntParam(query, paramLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	start, hasStart, err := parseIntParam(query, paramStart, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	end, hasEnd, err := parseIntParam(query, paramEnd, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case hasPage:
+		if !hasLimit {
+			limit = defaultPageLimit
+		}
+
+		return &pagination{paged: true, page: page, limit: limit}, nil
+	case hasEnd:
+		if end < start {
+			return nil, fmt.Errorf("%w: %s is lower than %s", storage.ErrBadRequest, paramEnd, paramStart)
+		}
+
+		return &pagination{start: start, end: end}, nil
+	case hasStart, hasLimit:
+		p := &pagination{start: start, end: -1}
+		if hasLimit {
+			p.end = start + limit
+		}
+
+		return p, nil
+	default:
+		return nil, nil
+	}
+}
+
+// bounds returns the start and end index of the pagination in a collection of total size.
+func (p *pagination) bounds(total int) (int, int) {
+	start, end := p.start, p.end
+	if p.paged {
+		start = (p.page - 1) * p.limit
+		end = start + p.limit
+	}
+
+	if end < 0 || end > total {
+		end = total
+	}
+
+	if start > end {
+		start = end
+	}
+
+	return start, end
+}
+
+// headers returns the 'X-Total-Count' header along with a 'Link' header (RFC 8288) that
+// contains the first, prev, next and last page urls, when paginating by pages.
+func (p *pagination) headers(r *http.Request, total int) http.Header {
+	headers := http.Header{}
+	headers.Set("X-Total-Count", strconv.Itoa(total))
+
+	if !p.paged || p.limit == 0 {
+		return headers
+	}
+
+	lastPage := (total + p.limit - 1) / p.limit
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := []string{p.link(r, 1, "first")}
+
+	if p.page > 1 {
+		prevPage := p.page - 1
+		if prevPage > lastPage {
+			prevPage = lastPage
+		}
+
+		links = append(links, p.link(r, prevPage, "prev"))
+	}
+
+	if p.page < lastPage {
+		links = append(links, p.link(r, p.page+1, "next"))
+	}
+
+	links = append(links, p.link(r, lastPage, "last"))
+
+	headers.Set("Link", strings.Join(links, ", "))
+
+	return headers
+}
+
+// link returns a single link header value, to the requested url with the provided page.
+func (p *pagination) link(r *http.Request, page int, rel string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	query := r.URL.Query()
+	query.Set(paramPage, strconv.Itoa(page))
+	query.Set(paramLimit, strconv.Itoa(p.limit))
+
+	u := url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path, RawQuery: query.Encode()}
+
+	return fmt.Sprintf("<%s>; rel=\"%s\"", u.String(), rel)
+}
+
+// parseIntParam parses an integer query parameter, which must be at least minimum.
+// Reports whether the parameter was provided.
+func parseIntParam(query url.Values, param string, minimum int) (int, bool, error) {
+	value := query.Get(param)
+	if value == "" {
+		return 0, false, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < minimum {
+		return 0, false, fmt.Errorf("%w: invalid %s %q", storage.ErrBadRequest, param, value)
+	}
+
+	return number, true, nil
+}
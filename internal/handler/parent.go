@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+// Parent scopes the handlers of nested routes, e.g. '/posts/{parentId}/comments', to the resources
+// that refer to the parent one through a foreign key. A nil parent doesn't restrict the resources.
+type Parent struct {
+	storageSvc storage.Storage
+	foreignKey string
+}
+
+// NewParent returns a new parent instance, for resources that refer to it with the foreign key.
+func NewParent(storageSvc storage.Storage, foreignKey string) *Parent {
+	return &Parent{storageSvc: storageSvc, foreignKey: foreignKey}
+}
+
+// resolve returns the parent resource of the request path. If the path contains a resource id,
+// it also verifies that the resource refers to the parent, otherwise it's reported as not found.
+func (p *Parent) resolve(r *http.Request, storageSvc storage.Storage) (storage.Resource, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	vars := mux.Vars(r)
+
+	parent, err := p.storageSvc.FindById(vars["parentId"])
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := vars["id"]
+	if !ok {
+		return parent, nil
+	}
+
+	resource, err := storageSvc.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if storage.FormatValue(resource[p.foreignKey]) != storage.FormatValue(parent[p.storageSvc.IDField()]) {
+		return nil, storage.ErrResourceNotFound
+	}
+
+	return parent, nil
+}
+
+// filter returns the conditions that match only the resources referring to the parent.
+func (p *Parent) filter(parent storage.Resource) storage.Filter {
+	if p == nil {
+		return nil
+	}
+
+	return storage.Filter{{Field: p.foreignKey, Values: []string{storage.FormatValue(parent[p.storageSvc.IDField()])}}}
+}
+
+// assign sets the foreign key of resource to refer to the parent.
+func (p *Parent) assign(resource, parent storage.Resource) {
+	if p == nil {
+		return
+	}
+
+	resource[p.foreignKey] = parent[p.storageSvc.IDField()]
+}
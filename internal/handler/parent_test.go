@@ -0,0 +1,207 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestParent(t *testing.T) {
+	comments := testRelationData["comments"]
+
+	type bodyError struct {
+		Error string `json:"error"`
+	}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         interface{}
+		statusCode   int
+		expectedData interface{}
+		wantErr      bool
+		err          error
+	}{
+		{
+			name:         "List children of parent",
+			method:       http.MethodGet,
+			path:         "/posts/1/comments",
+			statusCode:   http.StatusOK,
+			expectedData: []storage.Resource{comments[0], comments[1]},
+		},
+		{
+			name:         "List children of parent with filter",
+			method:       http.MethodGet,
+			path:         "/posts/1/comments?body=second%20comment",
+			statusCode:   http.StatusOK,
+			expectedData: []storage.Resource{comments[1]},
+		},
+		{
+			name:       "List children of not existing parent",
+			method:     http.MethodGet,
+			path:       "/posts/randomId/comments",
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+			err:        storage.ErrResourceNotFound,
+		},
+		{
+			name:       "List children of unrelated parent",
+			method:     http.MethodGet,
+			path:       "/albums/1/comments",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:         "List children of parent without any children yet",
+			method:       http.MethodGet,
+			path:         "/posts/1/replies",
+			statusCode:   http.StatusOK,
+			expectedData: []storage.Resource{},
+		},
+		{
+			name:         "Create first child of parent",
+			method:       http.MethodPost,
+			path:         "/posts/2/replies",
+			body:         storage.Resource{"id": "1", "body": "first reply"},
+			statusCode:   http.StatusCreated,
+			expectedData: storage.Resource{"id": "1", "body": "first reply", "postId": "2"},
+		},
+		{
+			name:         "Read child of parent",
+			method:       http.MethodGet,
+			path:         "/posts/2/comments/3",
+			statusCode:   http.StatusOK,
+			expectedData: comments[2],
+		},
+		{
+			name:       "Read child of another parent",
+			method:     http.MethodGet,
+			path:       "/posts/2/comments/1",
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+			err:        storage.ErrResourceNotFound,
+		},
+		{
+			name:         "Create child of parent",
+			method:       http.MethodPost,
+			path:         "/albums/1/photos",
+			body:         storage.Resource{"id": "3", "title": "sunset"},
+			statusCode:   http.StatusCreated,
+			expectedData: storage.Resource{"id": "3", "title": "sunset", "albumId": "1"},
+		},
+		{
+			name:       "Create child of not existing parent",
+			method:     http.MethodPost,
+			path:       "/albums/randomId/photos",
+			body:       storage.Resource{"title": "sunset"},
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+			err:        storage.ErrResourceNotFound,
+		},
+		{
+			name:       "Replace child of another parent",
+			method:     http.MethodPut,
+			path:       "/albums/1/photos/2",
+			body:       storage.Resource{"title": "party"},
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+			err:        storage.ErrResourceNotFound,
+		},
+		{
+			name:         "Replace child of parent",
+			method:       http.MethodPut,
+			path:         "/albums/2/photos/2",
+			body:         storage.Resource{"title": "party"},
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"id": "2", "title": "party", "albumId": "2"},
+		},
+		{
+			name:       "Update child of another parent",
+			method:     http.MethodPatch,
+			path:       "/albums/2/photos/1",
+			body:       storage.Resource{"title": "sea"},
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+			err:        storage.ErrResourceNotFound,
+		},
+		{
+			name:         "Update child of parent keeps the parent",
+			method:       http.MethodPatch,
+			path:         "/albums/1/photos/1",
+			body:         storage.Resource{"title": "sea", "albumId": "2"},
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"id": "1", "title": "sea", "albumId": "1"},
+		},
+		{
+			name:       "Delete child of another parent",
+			method:     http.MethodDelete,
+			path:       "/albums/2/photos/1",
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+			err:        storage.ErrResourceNotFound,
+		},
+		{
+			name:       "Delete child of parent",
+			method:     http.MethodDelete,
+			path:       "/albums/1/photos/1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:         "List children of parent after changes",
+			method:       http.MethodGet,
+			path:         "/albums/1/photos",
+			statusCode:   http.StatusOK,
+			expectedData: []storage.Resource{{"id": "3", "title": "sunset", "albumId": "1"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		url := fmt.Sprintf("%s%s", mockServer.URL, tt.path)
+
+		var reqBody bytes.Buffer
+		if tt.body != nil {
+			if err := json.NewEncoder(&reqBody).Encode(tt.body); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		req, err := http.NewRequest(tt.method, url, &reqBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf("%s: expected status code %v, but got %v", tt.name, tt.statusCode, resp.StatusCode)
+		}
+
+		if !tt.wantErr && tt.expectedData != nil {
+			var body interface{}
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := testJSON(t, tt.expectedData); !reflect.DeepEqual(body, expected) {
+				t.Fatalf("%s: expected body %v, but got %v", tt.name, expected, body)
+			}
+		} else if tt.wantErr {
+			var body bodyError
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Error != tt.err.Error() {
+				t.Fatalf("%s: expected error message %v, but got %v", tt.name, tt.err, body.Error)
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+	"github.com/chanioxaris/json-server/internal/web"
+)
+
+const (
+	paramSort   = "_sort"
+	paramOrder  = "_order"
+	paramFields = "_fields"
+)
+
+// reservedParams are query parameters that control the response, instead of filtering it.
+var reservedParams = map[string]bool{
+	paramSort:     true,
+	paramOrder:    true,
+	paramFields:   true,
+	paramPage:     true,
+	paramLimit:    true,
+	paramStart:    true,
+	paramEnd:      true,
+	paramCursor:   true,
+	paramEnvelope: true,
+	paramSearch:   true,
+	paramTokenize: true,
+	paramEmbed:    true,
+	paramExpand:   true,
+}
+
+// listQuery represents the parsed query parameters of a list request.
+type listQuery struct {
+	filter storage.Filter
+	sort   []storage.SortField
+	search *textSearch
+	paging *pagination
+	cursor *cursorPagination
+	fields []string
+}
+
+// parseListQuery parses the query parameters of a list request.
+func parseListQuery(query url.Values, idField string) (*listQuery, error) {
+	filter, err := parseFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	sortFields, err := parseSort(query)
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := parseSearch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if search != nil {
+		filter = append(filter, search.condition())
+	}
+
+	paging, err := parsePagination(query)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := parseCursorPagination(query, sortFields, idField)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cursors are positioned by the sort fields, so they can't follow the relevance order of a ranked search.
+	if cursor != nil && search != nil && search.rank && len(sortFields) == 0 {
+		return nil, fmt.Errorf("%w: %s can't be combined with %s, unless sorted by %s", storage.ErrBadRequest, paramCursor, paramTokenize, paramSort)
+	}
+
+	return &listQuery{
+		filter: filter,
+		sort:   sortFields,
+		search: search,
+		paging: paging,
+		cursor: cursor,
+		fields: web.QueryList(query, paramFields),
+	}, nil
+}
+
+// sortResources returns a copy of resources sorted as requested. Results of a ranked search
+// are sorted by relevance, unless sort fields are explicitly provided.
+func (q *listQuery) sortResources(resources []storage.Resource) []storage.Resource {
+	if q.search != nil && q.search.rank && len(q.sort) == 0 {
+		return q.search.sort(resources)
+	}
+
+	return storage.Sort(resources, q.sort)
+}
+
+// operatorSuffixes maps the query parameter suffixes to the filter operators they represent.
+var operatorSuffixes = map[string]storage.Operator{
+	"_ne":   storage.OperatorNe,
+	"_gte":  storage.OperatorGte,
+	"_lte":  storage.OperatorLte,
+	"_like": storage.OperatorLike,
+}
+
+// parseFilter builds a storage filter from the request query parameters. Every
+// parameter is treated as a field condition, where repeated keys are combined with OR.
+// A parameter suffix, e.g. 'published_gte', defines the operator of the condition.
+func parseFilter(query url.Values) (storage.Filter, error) {
+	params := make([]string, 0, len(query))
+	for param := range query {
+		if reservedParams[param] {
+			continue
+		}
+
+		params = append(params, param)
+	}
+
+	// Keep conditions order deterministic.
+	sort.Strings(params)
+
+	filter := make(storage.Filter, 0, len(params))
+	for _, param := range params {
+		field, operator := parseOperator(param)
+
+		condition, err := storage.NewCondition(field, operator, query[param])
+		if err != nil {
+			return nil, err
+		}
+
+		filter = append(filter, condition)
+	}
+
+	return filter, nil
+}
+
+// parseOperator splits a query parameter into the field and the operator denoted by its suffix.
+func parseOperator(param string) (string, storage.Operator) {
+	for suffix, operator := range operatorSuffixes {
+		if field := strings.TrimSuffix(param, suffix); field != param && field != "" {
+			return field, operator
+		}
+	}
+
+	return param, storage.OperatorEq
+}
+
+// parseSort builds the sort fields from the '_sort' and '_order' query parameters. Both
+// accept comma separated lists, where each order applies to the field in the same position.
+func parseSort(query url.Values) ([]storage.SortField, error) {
+	fields := web.QueryList(query, paramSort)
+	orders := web.QueryList(query, paramOrder)
+
+	if len(orders) > len(fields) {
+		return nil, fmt.Errorf("%w: more orders than sort fields", storage.ErrBadRequest)
+	}
+
+	sortFields := make([]storage.SortField, 0, len(fields))
+	for idx, field := range fields {
+		sortField := storage.SortField{Field: field}
+
+		if idx < len(orders) {
+			switch strings.ToLower(orders[idx]) {
+			case "asc":
+			case "desc":
+				sortField.Descending = true
+			default:
+				return nil, fmt.Errorf("%w: invalid order %q", storage.ErrBadRequest, orders[idx])
+			}
+		}
+
+		sortFields = append(sortFields, sortField)
+	}
+
+	return sortFields, nil
+}
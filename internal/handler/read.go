@@ -11,11 +11,29 @@ import (
 )
 
 // Read operates as a http handler, to return the requested resource by id.
-func Read(storageSvc storage.Storage) http.HandlerFunc {
+func Read(storageSvc storage.Storage, relations *Relations, parent *Parent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Read request path parameter id.
 		id := mux.Vars(r)["id"]
 
+		include, err := relations.parse(r.URL.Query())
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
+			return
+		}
+
+		// Resolve the parent resource of nested routes.
+		if _, err = parent.resolve(r, storageSvc); err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
 		// Find the resource with the requested id.
 		data, err := storageSvc.FindById(id)
 		if err != nil {
@@ -29,6 +47,16 @@ func Read(storageSvc storage.Storage) http.HandlerFunc {
 			return
 		}
 
-		web.Success(w, http.StatusOK, data)
+		// Include any requested relationships.
+		included, err := relations.include([]storage.Resource{data}, include)
+		if err != nil {
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		// Return only the requested fields.
+		fields := web.QueryList(r.URL.Query(), paramFields)
+
+		web.Success(w, http.StatusOK, storage.Project(included[0], fields))
 	}
 }
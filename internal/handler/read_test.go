@@ -27,6 +27,7 @@ func TestRead_Plural(t *testing.T) {
 		statusCode   int
 		key          string
 		id           string
+		query        string
 		expectedData interface{}
 		wantErr      bool
 		err          error
@@ -38,6 +39,14 @@ func TestRead_Plural(t *testing.T) {
 			id:           randomResource["id"].(string),
 			expectedData: randomResource,
 		},
+		{
+			name:         "Get plural resource with id and fields",
+			statusCode:   http.StatusOK,
+			key:          randomPluralKey,
+			id:           randomResource["id"].(string),
+			query:        "_fields=id,field_1",
+			expectedData: storage.Resource{"id": randomResource["id"], "field_1": randomResource["field_1"]},
+		},
 		{
 			name:       "Get plural resource invalid id",
 			statusCode: http.StatusNotFound,
@@ -51,7 +60,7 @@ func TestRead_Plural(t *testing.T) {
 	for _, tt := range testCases {
 		testResetData(tt.key)
 
-		url := fmt.Sprintf("%s/%s/%s", mockServer.URL, tt.key, tt.id)
+		url := fmt.Sprintf("%s/%s/%s?%s", mockServer.URL, tt.key, tt.id, tt.query)
 
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
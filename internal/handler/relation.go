@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+	"github.com/chanioxaris/json-server/internal/storage"
+	"github.com/chanioxaris/json-server/internal/web"
+)
+
+const (
+	paramEmbed  = "_embed"
+	paramExpand = "_expand"
+)
+
+// Relations resolves the relationships of a resource with the rest ones. Relationships are
+// based on foreign keys, named after the singular form of the parent resource, e.g. a 'comments'
+// resource refers to a 'posts' one with a 'postId' field.
+type Relations struct {
+	key             string
+	resourceStorage map[string]storage.Storage
+	inflector       *inflection.Inflector
+}
+
+// relationQuery represents the requested relationships to include in a response.
+type relationQuery struct {
+	// embed contains the child resource keys, e.g. 'comments' of a post.
+	embed []string
+	// expand contains the singular parent resource names, e.g. 'post' of a comment.
+	expand []string
+}
+
+// NewRelations returns a new relations instance for the resource key.
+func NewRelations(key string, resourceStorage map[string]storage.Storage, inflector *inflection.Inflector) *Relations {
+	return &Relations{key: key, resourceStorage: resourceStorage, inflector: inflector}
+}
+
+// foreignKey returns the name of the field that refers to a resource of the parent key, e.g. 'postId'.
+func (rel *Relations) foreignKey(parentKey string) string {
+	return rel.inflector.Singular(parentKey) + "Id"
+}
+
+// parse the '_embed' and '_expand' query parameters, validating that the requested resources exist.
+func (rel *Relations) parse(query url.Values) (*relationQuery, error) {
+	q := &relationQuery{
+		embed:  web.QueryList(query, paramEmbed),
+		expand: web.QueryList(query, paramExpand),
+	}
+
+	for _, child := range q.embed {
+		if _, ok := rel.resourceStorage[child]; !ok || child == "db" {
+			return nil, fmt.Errorf("%w: unknown resource %q to embed", storage.ErrBadRequest, child)
+		}
+	}
+
+	for _, parent := range q.expand {
+		if _, ok := rel.resourceStorage[rel.inflector.Plural(parent)]; !ok || parent == "db" {
+			return nil, fmt.Errorf("%w: unknown resource %q to expand", storage.ErrBadRequest, parent)
+		}
+	}
+
+	return q, nil
+}
+
+// include returns a copy of resources, with the requested relationships included.
+func (rel *Relations) include(resources []storage.Resource, q *relationQuery) ([]storage.Resource, error) {
+	if len(q.embed) == 0 && len(q.expand) == 0 {
+		return resources, nil
+	}
+
+	// Copy resources to avoid modifying the stored ones.
+	included := make([]storage.Resource, 0, len(resources))
+	for _, resource := range resources {
+		newResource := make(storage.Resource, len(resource))
+		for field, value := range resource {
+			newResource[field] = value
+		}
+
+		included = append(included, newResource)
+	}
+
+	for _, child := range q.embed {
+		if err := rel.embed(included, child); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, parent := range q.expand {
+		if err := rel.expand(included, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	return included, nil
+}
+
+// embed the resources of the child key, that refer to each of the resources, e.g. 'comments' of posts.
+func (rel *Relations) embed(resources []storage.Resource, child string) error {
+	foreignKey := rel.foreignKey(rel.key)
+	idField := rel.resourceStorage[rel.key].IDField()
+
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		ids = append(ids, storage.FormatValue(resource[idField]))
+	}
+
+	children, err := rel.resourceStorage[child].Find(storage.Filter{{Field: foreignKey, Values: ids}})
+	if err != nil {
+		return err
+	}
+
+	// Group children by the id they refer to.
+	grouped := make(map[string][]storage.Resource)
+	for _, c := range children {
+		id := storage.FormatValue(c[foreignKey])
+		grouped[id] = append(grouped[id], c)
+	}
+
+	for _, resource := range resources {
+		embedded, ok := grouped[storage.FormatValue(resource[idField])]
+		if !ok {
+			embedded = make([]storage.Resource, 0)
+		}
+
+		resource[child] = embedded
+	}
+
+	return nil
+}
+
+// expand the parent resource, that each of the resources refers to, e.g. 'post' of comments.
+func (rel *Relations) expand(resources []storage.Resource, parent string) error {
+	parentKey := rel.inflector.Plural(parent)
+	foreignKey := rel.foreignKey(parentKey)
+
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if id, ok := resource[foreignKey]; ok {
+			ids = append(ids, storage.FormatValue(id))
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	parentStorage := rel.resourceStorage[parentKey]
+
+	parents, err := parentStorage.Find(storage.Filter{{Field: parentStorage.IDField(), Values: ids}})
+	if err != nil {
+		return err
+	}
+
+	parentsByID := make(map[string]storage.Resource, len(parents))
+	for _, p := range parents {
+		parentsByID[storage.FormatValue(p[parentStorage.IDField()])] = p
+	}
+
+	for _, resource := range resources {
+		id, ok := resource[foreignKey]
+		if !ok {
+			continue
+		}
+
+		if p, ok := parentsByID[storage.FormatValue(id)]; ok {
+			resource[parent] = p
+		}
+	}
+
+	return nil
+}
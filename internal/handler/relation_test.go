@@ -0,0 +1,173 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestRelations(t *testing.T) {
+	posts := testRelationData["posts"]
+	comments := testRelationData["comments"]
+	people := testRelationData["people"]
+	pets := testRelationData["pets"]
+
+	type bodyError struct {
+		Error string `json:"error"`
+	}
+
+	testCases := []struct {
+		name         string
+		statusCode   int
+		path         string
+		expectedData interface{}
+		wantErr      bool
+		err          error
+	}{
+		{
+			name:       "Read resource with embedded children",
+			statusCode: http.StatusOK,
+			path:       "/posts/1?_embed=comments",
+			expectedData: storage.Resource{
+				"id":       "1",
+				"title":    "json-server",
+				"comments": []interface{}{testJSON(t, comments[0]), testJSON(t, comments[1])},
+			},
+		},
+		{
+			name:       "List resources with embedded children",
+			statusCode: http.StatusOK,
+			path:       "/posts?_embed=comments&_fields=id,comments.id",
+			expectedData: []interface{}{
+				map[string]interface{}{
+					"id":       "1",
+					"comments": []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}},
+				},
+				map[string]interface{}{
+					"id":       "2",
+					"comments": []interface{}{map[string]interface{}{"id": "3"}},
+				},
+			},
+		},
+		{
+			name:       "List resources with expanded parent",
+			statusCode: http.StatusOK,
+			path:       "/comments?_expand=post&_fields=id,post.id",
+			expectedData: []interface{}{
+				map[string]interface{}{"id": "1", "post": map[string]interface{}{"id": "1"}},
+				map[string]interface{}{"id": "2", "post": map[string]interface{}{"id": "1"}},
+				map[string]interface{}{"id": "3", "post": map[string]interface{}{"id": "2"}},
+				map[string]interface{}{"id": "4"},
+			},
+		},
+		{
+			name:       "Read resource with expanded parent",
+			statusCode: http.StatusOK,
+			path:       "/comments/3?_expand=post",
+			expectedData: storage.Resource{
+				"id":     "3",
+				"body":   "third comment",
+				"postId": "2",
+				"post":   testJSON(t, posts[1]),
+			},
+		},
+		{
+			name:       "Read resource with expanded irregular parent",
+			statusCode: http.StatusOK,
+			path:       "/pets/1?_expand=person",
+			expectedData: storage.Resource{
+				"id":       "1",
+				"name":     "rex",
+				"personId": "1",
+				"person":   testJSON(t, people[0]),
+			},
+		},
+		{
+			name:       "Read irregular resource with embedded children",
+			statusCode: http.StatusOK,
+			path:       "/people/1?_embed=pets",
+			expectedData: storage.Resource{
+				"id":   "1",
+				"name": "typicode",
+				"pets": []interface{}{testJSON(t, pets[0])},
+			},
+		},
+		{
+			name:         "List resources without relations after including them",
+			statusCode:   http.StatusOK,
+			path:         "/posts",
+			expectedData: testJSON(t, posts),
+		},
+		{
+			name:       "List resources with unknown embedded resource",
+			statusCode: http.StatusBadRequest,
+			path:       "/posts?_embed=randomKey",
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+		{
+			name:       "Read resource with unknown expanded resource",
+			statusCode: http.StatusBadRequest,
+			path:       "/comments/1?_expand=randomKey",
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		url := fmt.Sprintf("%s%s", mockServer.URL, tt.path)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf("expected status code %v, but got %v", tt.statusCode, resp.StatusCode)
+		}
+
+		if !tt.wantErr {
+			var body interface{}
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := testJSON(t, tt.expectedData); !reflect.DeepEqual(body, expected) {
+				t.Fatalf("expected body %v, but got %v", expected, body)
+			}
+		} else {
+			var body bodyError
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Error != tt.err.Error() {
+				t.Fatalf("expected error message %v, but got %v", tt.err, body.Error)
+			}
+		}
+	}
+}
+
+// testJSON converts value to its generic json decoded form, to be comparable with response bodies.
+func testJSON(t *testing.T, value interface{}) interface{} {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded interface{}
+	if err = json.Unmarshal(valueBytes, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded
+}
@@ -12,7 +12,7 @@ import (
 )
 
 // Replace operates as a http handler, to replace an existing resource.
-func Replace(storageSvc storage.Storage) http.HandlerFunc {
+func Replace(storageSvc storage.Storage, parent *Parent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Read request path parameter id.
 		id := mux.Vars(r)["id"]
@@ -25,11 +25,27 @@ func Replace(storageSvc storage.Storage) http.HandlerFunc {
 		}
 
 		// Check if request body is empty, or contains only id.
-		if _, ok := newResource["id"]; len(newResource) == 0 || (len(newResource) == 1 && ok) {
+		if _, ok := newResource[storageSvc.IDField()]; len(newResource) == 0 || (len(newResource) == 1 && ok) {
 			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
 			return
 		}
 
+		// Resolve the parent resource of nested routes.
+		parentResource, err := parent.resolve(r, storageSvc)
+		if err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		// Refer to the parent resource of nested routes.
+		parent.assign(newResource, parentResource)
+
 		// Replace the resource.
 		data, err := storageSvc.Replace(id, newResource)
 		if err != nil {
@@ -39,6 +55,12 @@ func Replace(storageSvc storage.Storage) http.HandlerFunc {
 				return
 			}
 
+			// Invalid resource for the storage.
+			if errors.Is(err, storage.ErrBadRequest) {
+				web.Error(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
 			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
 			return
 		}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+const (
+	paramSearch   = "q"
+	paramTokenize = "_tokenize"
+)
+
+// textSearch represents a full text search over all string values of resources.
+type textSearch struct {
+	terms []string
+	rank  bool
+}
+
+// parseSearch builds the full text search from the 'q' query parameter. Returns nil if no
+// search is requested. When '_tokenize' is enabled, the search is split into whitespace
+// separated terms and results are ranked by the number of hits.
+func parseSearch(query url.Values) (*textSearch, error) {
+	q := strings.TrimSpace(query.Get(paramSearch))
+	if q == "" {
+		return nil, nil
+	}
+
+	tokenize, err := strconv.ParseBool(query.Get(paramTokenize))
+	if err != nil && query.Get(paramTokenize) != "" {
+		return nil, fmt.Errorf("%w: invalid %s", storage.ErrBadRequest, paramTokenize)
+	}
+
+	if !tokenize {
+		return &textSearch{terms: []string{q}}, nil
+	}
+
+	return &textSearch{terms: strings.Fields(q), rank: true}, nil
+}
+
+// condition returns the filter condition that matches resources containing any of the search terms.
+func (s *textSearch) condition() storage.Condition {
+	return storage.Condition{Operator: storage.OperatorSearch, Values: s.terms}
+}
+
+// sort returns a copy of resources stably sorted by the number of search hits, in descending order.
+func (s *textSearch) sort(resources []storage.Resource) []storage.Resource {
+	hits := make(map[int]int, len(resources))
+	ranked := make([]int, len(resources))
+	for idx, resource := range resources {
+		ranked[idx] = idx
+		hits[idx] = storage.SearchHits(resource, s.terms)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return hits[ranked[i]] > hits[ranked[j]]
+	})
+
+	sorted := make([]storage.Resource, 0, len(resources))
+	for _, idx := range ranked {
+		sorted = append(sorted, resources[idx])
+	}
+
+	return sorted
+}
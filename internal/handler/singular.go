@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+	"github.com/chanioxaris/json-server/internal/web"
+)
+
+// ReadSingular operates as a http handler, to return a singular resource.
+func ReadSingular(singularSvc storage.SingularStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := singularSvc.Get()
+		if err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		// Return only the requested fields.
+		fields := web.QueryList(r.URL.Query(), paramFields)
+
+		web.Success(w, http.StatusOK, storage.Project(data, fields))
+	}
+}
+
+// ReplaceSingular operates as a http handler, to replace a singular resource.
+func ReplaceSingular(singularSvc storage.SingularStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read and decode request body.
+		var newResource storage.Resource
+		if err := json.NewDecoder(r.Body).Decode(&newResource); err != nil || newResource == nil {
+			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
+			return
+		}
+
+		// Replace the resource.
+		data, err := singularSvc.Replace(newResource)
+		if err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		web.Success(w, http.StatusOK, data)
+	}
+}
+
+// UpdateSingular operates as a http handler, to update a singular resource.
+func UpdateSingular(singularSvc storage.SingularStorage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read and decode request body.
+		var newResource storage.Resource
+		if err := json.NewDecoder(r.Body).Decode(&newResource); err != nil {
+			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
+			return
+		}
+
+		// Check if request body is empty.
+		if len(newResource) == 0 {
+			web.Error(w, http.StatusBadRequest, storage.ErrBadRequest.Error())
+			return
+		}
+
+		// Update the resource.
+		data, err := singularSvc.Update(newResource)
+		if err != nil {
+			// Resource not found.
+			if errors.Is(err, storage.ErrResourceNotFound) {
+				web.Error(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			web.Error(w, http.StatusInternalServerError, storage.ErrInternalServerError.Error())
+			return
+		}
+
+		web.Success(w, http.StatusOK, data)
+	}
+}
@@ -0,0 +1,130 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestSingular(t *testing.T) {
+	type bodyError struct {
+		Error string `json:"error"`
+	}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         interface{}
+		statusCode   int
+		expectedData storage.Resource
+		wantErr      bool
+		err          error
+	}{
+		{
+			name:         "Read singular resource",
+			method:       http.MethodGet,
+			path:         "/profile",
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"name": "typicode", "age": float64(30)},
+		},
+		{
+			name:         "Read singular resource with fields",
+			method:       http.MethodGet,
+			path:         "/profile?_fields=name",
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"name": "typicode"},
+		},
+		{
+			name:         "Update singular resource",
+			method:       http.MethodPatch,
+			path:         "/profile",
+			body:         storage.Resource{"age": float64(31)},
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"name": "typicode", "age": float64(31)},
+		},
+		{
+			name:       "Update singular resource with empty body",
+			method:     http.MethodPatch,
+			path:       "/profile",
+			body:       storage.Resource{},
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+		{
+			name:         "Replace singular resource",
+			method:       http.MethodPut,
+			path:         "/profile",
+			body:         storage.Resource{"name": "chanioxaris"},
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"name": "chanioxaris"},
+		},
+		{
+			name:       "Replace singular resource with invalid body",
+			method:     http.MethodPut,
+			path:       "/profile",
+			body:       []string{"name"},
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+			err:        storage.ErrBadRequest,
+		},
+		{
+			name:         "Read singular resource after changes",
+			method:       http.MethodGet,
+			path:         "/profile",
+			statusCode:   http.StatusOK,
+			expectedData: storage.Resource{"name": "chanioxaris"},
+		},
+	}
+
+	for _, tt := range testCases {
+		url := fmt.Sprintf("%s%s", mockServer.URL, tt.path)
+
+		var reqBody bytes.Buffer
+		if tt.body != nil {
+			if err := json.NewEncoder(&reqBody).Encode(tt.body); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		req, err := http.NewRequest(tt.method, url, &reqBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf("%s: expected status code %v, but got %v", tt.name, tt.statusCode, resp.StatusCode)
+		}
+
+		if !tt.wantErr {
+			var body storage.Resource
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(body, tt.expectedData) {
+				t.Fatalf("%s: expected body %v, but got %v", tt.name, tt.expectedData, body)
+			}
+		} else {
+			var body bodyError
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Error != tt.err.Error() {
+				t.Fatalf("%s: expected error message %v, but got %v", tt.name, tt.err, body.Error)
+			}
+		}
+	}
+}
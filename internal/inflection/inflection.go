@@ -0,0 +1,98 @@
+// Package inflection provides conversion of resource names between their singular and plural forms.
+package inflection
+
+import (
+	"strings"
+)
+
+// defaultIrregular contains common english words, that don't follow the pluralization rules.
+var defaultIrregular = map[string]string{
+	"person": "people",
+	"child":  "children",
+	"man":    "men",
+	"woman":  "women",
+	"mouse":  "mice",
+	"goose":  "geese",
+	"tooth":  "teeth",
+	"foot":   "feet",
+}
+
+// Inflector converts resource names between singular and plural forms. It's based on english
+// pluralization rules, which can be overridden by irregular words.
+type Inflector struct {
+	plurals   map[string]string
+	singulars map[string]string
+}
+
+// New returns a new inflector instance. Irregular maps singular words to their plural form,
+// in addition to a set of common english irregular words.
+func New(irregular map[string]string) *Inflector {
+	i := &Inflector{
+		plurals:   make(map[string]string),
+		singulars: make(map[string]string),
+	}
+
+	for singular, plural := range defaultIrregular {
+		i.AddIrregular(singular, plural)
+	}
+
+	for singular, plural := range irregular {
+		i.AddIrregular(singular, plural)
+	}
+
+	return i
+}
+
+// AddIrregular registers a word that doesn't follow the pluralization rules.
+func (i *Inflector) AddIrregular(singular, plural string) {
+	i.plurals[singular] = plural
+	i.singulars[plural] = singular
+}
+
+// Singular returns the singular form of a plural word, e.g. 'posts' to 'post'.
+func (i *Inflector) Singular(plural string) string {
+	if singular, ok := i.singulars[plural]; ok {
+		return singular
+	}
+
+	switch {
+	case strings.HasSuffix(plural, "ies") && len(plural) > 3:
+		return strings.TrimSuffix(plural, "ies") + "y"
+	case hasAnySuffix(plural, "sses", "xes", "zes", "ches", "shes"):
+		return strings.TrimSuffix(plural, "es")
+	case strings.HasSuffix(plural, "s") && !strings.HasSuffix(plural, "ss"):
+		return strings.TrimSuffix(plural, "s")
+	default:
+		return plural
+	}
+}
+
+// Plural returns the plural form of a singular word, e.g. 'post' to 'posts'.
+func (i *Inflector) Plural(singular string) string {
+	if plural, ok := i.plurals[singular]; ok {
+		return plural
+	}
+
+	switch {
+	case strings.HasSuffix(singular, "y") && len(singular) > 1 && !isVowel(singular[len(singular)-2]):
+		return strings.TrimSuffix(singular, "y") + "ies"
+	case hasAnySuffix(singular, "s", "x", "z", "ch", "sh"):
+		return singular + "es"
+	default:
+		return singular + "s"
+	}
+}
+
+func hasAnySuffix(word string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
@@ -0,0 +1,37 @@
+package inflection_test
+
+import (
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+)
+
+func TestInflector(t *testing.T) {
+	inflector := inflection.New(map[string]string{"datum": "data", "octopus": "octopi"})
+
+	testCases := []struct {
+		name     string
+		singular string
+		plural   string
+	}{
+		{name: "Regular word", singular: "post", plural: "posts"},
+		{name: "Word ending in consonant and y", singular: "category", plural: "categories"},
+		{name: "Word ending in vowel and y", singular: "day", plural: "days"},
+		{name: "Word ending in s", singular: "address", plural: "addresses"},
+		{name: "Word ending in x", singular: "box", plural: "boxes"},
+		{name: "Word ending in ch", singular: "match", plural: "matches"},
+		{name: "Default irregular word", singular: "person", plural: "people"},
+		{name: "Configured irregular word", singular: "datum", plural: "data"},
+		{name: "Configured irregular word ending in s", singular: "octopus", plural: "octopi"},
+	}
+
+	for _, tt := range testCases {
+		if got := inflector.Plural(tt.singular); got != tt.plural {
+			t.Fatalf("%s: expected plural %v, but got %v", tt.name, tt.plural, got)
+		}
+
+		if got := inflector.Singular(tt.plural); got != tt.singular {
+			t.Fatalf("%s: expected singular %v, but got %v", tt.name, tt.singular, got)
+		}
+	}
+}
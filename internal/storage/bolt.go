@@ -0,0 +1,603 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// boltSingularBucket contains the singular resources, as it's unlikely to be a resource key.
+	boltSingularBucket = []byte("\x00singular")
+	// boltDocsBucket contains the json documents of a resource key, by their insertion sequence.
+	boltDocsBucket = []byte("docs")
+	// boltIDsBucket indexes the insertion sequences of a resource key, by the normalized value of ids.
+	boltIDsBucket = []byte("ids")
+	// boltDuplicatesBucket contains the ids of resources that aren't indexed, as another resource has the
+	// same id, by their insertion sequence.
+	boltDuplicatesBucket = []byte("duplicates")
+	// boltIDFieldKey holds the name of the id field, that resources of a key are indexed by.
+	boltIDFieldKey = []byte("idField")
+)
+
+// BoltDatabase holds a bolt database, shared by the bolt storage of every key. Every resource key is
+// stored in its own bucket.
+type BoltDatabase struct {
+	db *bolt.DB
+}
+
+// NewBoltDatabase opens the bolt database of the file, creating it if it doesn't exist, and returns a
+// new bolt database instance.
+func NewBoltDatabase(filename string) (*BoltDatabase, error) {
+	// The file is locked while open, so fail instead of waiting forever on another process.
+	db, err := bolt.Open(filename, 0644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltDatabase{db: db}, nil
+}
+
+// Keys returns the keys of resources and singular resources of the database, sorted by name.
+func (d *BoltDatabase) Keys() ([]string, []string, error) {
+	resourceKeys := make([]string, 0)
+	singularKeys := make([]string, 0)
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if string(name) != string(boltSingularBucket) {
+				resourceKeys = append(resourceKeys, string(name))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		singular := tx.Bucket(boltSingularBucket)
+		if singular == nil {
+			return nil
+		}
+
+		return singular.ForEach(func(name, _ []byte) error {
+			singularKeys = append(singularKeys, string(name))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resourceKeys, singularKeys, nil
+}
+
+// Import replaces the contents of the database with the ones of the data file.
+func (d *BoltDatabase) Import(filename string) error {
+	data, singular, err := readFile(filename, FileKey(filename))
+	if err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		names := make([][]byte, 0)
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if err = tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+
+		for key, resources := range data {
+			// Resources are imported with the default id field, and indexed again if storage uses another one.
+			bucket, err := createBoltResourceBucket(tx, key, "id")
+			if err != nil {
+				return err
+			}
+
+			for _, resource := range resources {
+				if err = insertBoltResource(bucket, "id", resource); err != nil {
+					return err
+				}
+			}
+		}
+
+		singularBucket, err := tx.CreateBucket(boltSingularBucket)
+		if err != nil {
+			return err
+		}
+
+		for key, resource := range singular {
+			doc, err := json.Marshal(resource)
+			if err != nil {
+				return err
+			}
+
+			if err = singularBucket.Put([]byte(key), doc); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close the database.
+func (d *BoltDatabase) Close() error {
+	return d.db.Close()
+}
+
+// Bolt implements the storage interface, and uses a bolt database as 'database'.
+type Bolt struct {
+	database *BoltDatabase
+	key      []byte
+	options
+}
+
+// NewBolt returns a new bolt instance. Resources of the key are indexed again, if they were indexed
+// by another id field.
+func NewBolt(database *BoltDatabase, key string, opts ...Option) (*Bolt, error) {
+	b := &Bolt{database: database, key: []byte(key), options: newOptions(opts)}
+
+	// The storage for all resources isn't bound to a key.
+	if key == "" {
+		return b, nil
+	}
+
+	err := database.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.key)
+		if bucket == nil || string(bucket.Get(boltIDFieldKey)) == b.idField {
+			return nil
+		}
+
+		for _, name := range [][]byte{boltIDsBucket, boltDuplicatesBucket} {
+			if err := bucket.DeleteBucket(name); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+				return err
+			}
+
+			if _, err := bucket.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+
+		err := bucket.Bucket(boltDocsBucket).ForEach(func(seq, doc []byte) error {
+			var resource Resource
+			if err := json.Unmarshal(doc, &resource); err != nil {
+				return err
+			}
+
+			return indexBoltResource(bucket, b.idField, resource, seq)
+		})
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(boltIDFieldKey, []byte(b.idField))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// IDField returns the name of the id field of bolt resources.
+func (b *Bolt) IDField() string {
+	return b.idField
+}
+
+// Find all bolt resources for the specific key, that match the provided filter.
+func (b *Bolt) Find(filter Filter) ([]Resource, error) {
+	var resources []Resource
+
+	err := b.database.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.key)
+		if bucket == nil {
+			return ErrResourceNotFound
+		}
+
+		var err error
+		resources, err = boltResources(bucket)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return filterResources(resources, filter), nil
+}
+
+// FindById a bolt resource for the specific key, using the index of ids.
+func (b *Bolt) FindById(id string) (Resource, error) {
+	var resource Resource
+
+	err := b.database.db.View(func(tx *bolt.Tx) error {
+		var err error
+		resource, _, err = b.findById(tx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// Create a new bolt resource for the specific key.
+func (b *Bolt) Create(newResource Resource) (Resource, error) {
+	err := b.database.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.key)
+		if bucket == nil {
+			return ErrResourceNotFound
+		}
+
+		_, ok := newResource[b.idField]
+		if !ok {
+			resources, err := boltResources(bucket)
+			if err != nil {
+				return err
+			}
+
+			id, err := b.idGenerator.Generate(resourceIDs(resources, b.idField))
+			if err != nil {
+				return err
+			}
+
+			newResource[b.idField] = id
+		} else if bucket.Bucket(boltIDsBucket).Get([]byte(FormatID(newResource[b.idField]))) != nil {
+			return ErrResourceAlreadyExists
+		}
+
+		return insertBoltResource(bucket, b.idField, newResource)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+// Replace an existing bolt resource for the specific key.
+func (b *Bolt) Replace(id string, replaced Resource) (Resource, error) {
+	err := b.database.db.Update(func(tx *bolt.Tx) error {
+		// Check if resource with the requested id exists.
+		current, seq, err := b.findById(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Keep the id, along with its original type.
+		replaced[b.idField] = current[b.idField]
+
+		return putBoltDoc(tx.Bucket(b.key), seq, replaced)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// Update an existing bolt resource for the specific key.
+func (b *Bolt) Update(id string, updatedReq Resource) (Resource, error) {
+	var updated Resource
+
+	err := b.database.db.Update(func(tx *bolt.Tx) error {
+		// Check if resource with the requested id exists and retrieve it.
+		var (
+			seq []byte
+			err error
+		)
+		updated, seq, err = b.findById(tx, id)
+		if err != nil {
+			return err
+		}
+
+		currentID := updated[b.idField]
+
+		// Apply any changes to current resource.
+		for key, val := range updatedReq {
+			updated[key] = val
+		}
+
+		// Keep the id, along with its original type.
+		updated[b.idField] = currentID
+
+		return putBoltDoc(tx.Bucket(b.key), seq, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Delete an existing bolt resource for the specific key.
+func (b *Bolt) Delete(id string) error {
+	return b.database.db.Update(func(tx *bolt.Tx) error {
+		// Check if resource with the requested id exists.
+		_, seq, err := b.findById(tx, id)
+		if err != nil {
+			return err
+		}
+
+		bucket := tx.Bucket(b.key)
+		if err = bucket.Bucket(boltDocsBucket).Delete(seq); err != nil {
+			return err
+		}
+
+		if err = bucket.Bucket(boltIDsBucket).Delete([]byte(id)); err != nil {
+			return err
+		}
+
+		return reindexBoltDuplicate(bucket, id)
+	})
+}
+
+// DB returns all the bolt resources.
+func (b *Bolt) DB() (Database, error) {
+	data := make(Database)
+
+	err := b.database.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+			if string(name) == string(boltSingularBucket) {
+				return nil
+			}
+
+			resources, err := boltResources(bucket)
+			if err != nil {
+				return err
+			}
+
+			data[string(name)] = resources
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// findById a bolt resource for the specific key, along with its insertion sequence.
+func (b *Bolt) findById(tx *bolt.Tx, id string) (Resource, []byte, error) {
+	bucket := tx.Bucket(b.key)
+	if bucket == nil {
+		return nil, nil, ErrResourceNotFound
+	}
+
+	seq := bucket.Bucket(boltIDsBucket).Get([]byte(id))
+	if seq == nil {
+		return nil, nil, ErrResourceNotFound
+	}
+
+	var resource Resource
+	if err := json.Unmarshal(bucket.Bucket(boltDocsBucket).Get(seq), &resource); err != nil {
+		return nil, nil, err
+	}
+
+	// Values are only valid during the transaction.
+	return resource, append([]byte(nil), seq...), nil
+}
+
+// createBoltResourceBucket creates the bucket of a resource key, indexed by the id field.
+func createBoltResourceBucket(tx *bolt.Tx, key, idField string) (*bolt.Bucket, error) {
+	bucket, err := tx.CreateBucket([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = bucket.CreateBucket(boltDocsBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err = bucket.CreateBucket(boltIDsBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err = bucket.CreateBucket(boltDuplicatesBucket); err != nil {
+		return nil, err
+	}
+
+	if err = bucket.Put(boltIDFieldKey, []byte(idField)); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// boltResources returns all the resources of the bucket, in insertion order.
+func boltResources(bucket *bolt.Bucket) ([]Resource, error) {
+	resources := make([]Resource, 0)
+
+	err := bucket.Bucket(boltDocsBucket).ForEach(func(_, doc []byte) error {
+		var resource Resource
+		if err := json.Unmarshal(doc, &resource); err != nil {
+			return err
+		}
+
+		resources = append(resources, resource)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// insertBoltResource appends the resource to the bucket, indexed by the value of its id field.
+func insertBoltResource(bucket *bolt.Bucket, idField string, resource Resource) error {
+	next, err := bucket.Bucket(boltDocsBucket).NextSequence()
+	if err != nil {
+		return err
+	}
+
+	// Big endian sequences keep the insertion order, as keys are sorted byte-wise.
+	seq := make([]byte, 8)
+	binary.BigEndian.PutUint64(seq, next)
+
+	if err = putBoltDoc(bucket, seq, resource); err != nil {
+		return err
+	}
+
+	return indexBoltResource(bucket, idField, resource, seq)
+}
+
+// indexBoltResource indexes the sequence of the resource by its id. The first resource wins, if
+// ids are duplicate, while the rest are kept as duplicates, to be indexed once it's deleted.
+func indexBoltResource(bucket *bolt.Bucket, idField string, resource Resource, seq []byte) error {
+	id := []byte(FormatID(resource[idField]))
+
+	ids := bucket.Bucket(boltIDsBucket)
+	if ids.Get(id) != nil {
+		return bucket.Bucket(boltDuplicatesBucket).Put(seq, id)
+	}
+
+	return ids.Put(id, seq)
+}
+
+// reindexBoltDuplicate indexes the first of the resources with the id of a deleted resource, if any.
+func reindexBoltDuplicate(bucket *bolt.Bucket, id string) error {
+	duplicates := bucket.Bucket(boltDuplicatesBucket)
+	if duplicates == nil {
+		return nil
+	}
+
+	c := duplicates.Cursor()
+	for seq, duplicateID := c.First(); seq != nil; seq, duplicateID = c.Next() {
+		if string(duplicateID) != id {
+			continue
+		}
+
+		if err := bucket.Bucket(boltIDsBucket).Put([]byte(id), append([]byte(nil), seq...)); err != nil {
+			return err
+		}
+
+		return c.Delete()
+	}
+
+	return nil
+}
+
+// putBoltDoc stores the json document of the resource, at the insertion sequence.
+func putBoltDoc(bucket *bolt.Bucket, seq []byte, resource Resource) error {
+	doc, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Bucket(boltDocsBucket).Put(seq, doc)
+}
+
+// SingularBolt implements the singular storage interface, and uses a bolt database as 'database'.
+type SingularBolt struct {
+	database *BoltDatabase
+	key      []byte
+}
+
+// NewSingularBolt returns a new singular bolt instance.
+func NewSingularBolt(database *BoltDatabase, key string) (*SingularBolt, error) {
+	return &SingularBolt{database: database, key: []byte(key)}, nil
+}
+
+// Get the singular bolt resource for the specific key.
+func (b *SingularBolt) Get() (Resource, error) {
+	var resource Resource
+
+	err := b.database.db.View(func(tx *bolt.Tx) error {
+		var err error
+		resource, err = b.get(tx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// Replace the singular bolt resource for the specific key.
+func (b *SingularBolt) Replace(replaced Resource) (Resource, error) {
+	err := b.database.db.Update(func(tx *bolt.Tx) error {
+		if _, err := b.get(tx); err != nil {
+			return err
+		}
+
+		return b.set(tx, replaced)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// Update the singular bolt resource for the specific key.
+func (b *SingularBolt) Update(updatedReq Resource) (Resource, error) {
+	var updated Resource
+
+	err := b.database.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		updated, err = b.get(tx)
+		if err != nil {
+			return err
+		}
+
+		// Apply any changes to current resource.
+		for key, val := range updatedReq {
+			updated[key] = val
+		}
+
+		return b.set(tx, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (b *SingularBolt) get(tx *bolt.Tx) (Resource, error) {
+	bucket := tx.Bucket(boltSingularBucket)
+	if bucket == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	doc := bucket.Get(b.key)
+	if doc == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	var resource Resource
+	if err := json.Unmarshal(doc, &resource); err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (b *SingularBolt) set(tx *bolt.Tx, resource Resource) error {
+	doc, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(boltSingularBucket).Put(b.key, doc)
+}
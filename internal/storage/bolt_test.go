@@ -0,0 +1,277 @@
+package storage_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestBolt(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db.json")
+	content := `{"posts": [{"id": 1, "title": "json-server"}, {"id": 2, "title": "json-server in go"}], "profile": {"name": "typicode"}}`
+	if err = ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := storage.NewBoltDatabase(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	resourceKeys, singularKeys, err := database.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"posts"}; !reflect.DeepEqual(resourceKeys, expected) {
+		t.Fatalf("expected resource keys %v, but got %v", expected, resourceKeys)
+	}
+
+	if expected := []string{"profile"}; !reflect.DeepEqual(singularKeys, expected) {
+		t.Fatalf("expected singular keys %v, but got %v", expected, singularKeys)
+	}
+
+	posts, err := storage.NewBolt(database, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := posts.Create(storage.Resource{"title": "json-server in bolt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created["id"] != float64(3) {
+		t.Fatalf("expected id %v, but got %v", float64(3), created["id"])
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "3", "title": "duplicate"}); !errors.Is(err, storage.ErrResourceAlreadyExists) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceAlreadyExists, err)
+	}
+
+	if _, err = posts.Update("1", storage.Resource{"id": "10", "title": "json-server in js"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Replace("3", storage.Resource{"title": "json-server in kv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = posts.Delete("2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = posts.Delete("2"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	got, err := posts.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []storage.Resource{
+		{"id": float64(1), "title": "json-server in js"},
+		{"id": float64(3), "title": "json-server in kv"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+
+	comments, err := storage.NewBolt(database, "comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = comments.Find(nil); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	profile, err := storage.NewSingularBolt(database, "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = profile.Update(storage.Resource{"age": float64(30)}); err != nil {
+		t.Fatal(err)
+	}
+
+	gotProfile, err := profile.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"name": "typicode", "age": float64(30)}); !reflect.DeepEqual(gotProfile, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, gotProfile)
+	}
+
+	db, err := storage.NewBolt(database, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expectedDB := (storage.Database{"posts": expected}); !reflect.DeepEqual(gotDB, expectedDB) {
+		t.Fatalf("expected data %v, but got %v", expectedDB, gotDB)
+	}
+}
+
+func TestBolt_IDField(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db.json")
+	if err = ioutil.WriteFile(file, []byte(`{"products": [{"sku": "a1", "id": "other"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := storage.NewBoltDatabase(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resources must be indexed again by the id field of storage.
+	products, err := storage.NewBolt(database, "products", storage.WithIDField("sku"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = products.FindById("other"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	got, err := products.FindById("a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"sku": "a1", "id": "other"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+}
+
+func TestBolt_DuplicateIDs(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db.json")
+	content := `{"posts": [{"id": 1, "title": "first"}, {"id": 2, "title": "other"}, {"id": 1, "title": "second"}, {"id": 1, "title": "third"}]}`
+	if err = ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := storage.NewBoltDatabase(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	posts, err := storage.NewBolt(database, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Resources with duplicate ids are served in insertion order, as the previous ones are deleted.
+	for _, expected := range []storage.Resource{
+		{"id": float64(1), "title": "first"},
+		{"id": float64(1), "title": "second"},
+		{"id": float64(1), "title": "third"},
+	} {
+		got, err := posts.FindById("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected resource %v, but got %v", expected, got)
+		}
+
+		if _, err = posts.Update("1", storage.Resource{"views": float64(1)}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = posts.Delete("1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = posts.FindById("1"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	got, err := posts.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []storage.Resource{{"id": float64(2), "title": "other"}}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+
+	// Resources indexed again by another id field keep their duplicates.
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	byTitle, err := storage.NewBolt(database, "posts", storage.WithIDField("title"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = byTitle.FindById("second"); err != nil {
+		t.Fatal(err)
+	}
+
+	byID, err := storage.NewBolt(database, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = byID.Delete("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	gotResource, err := byID.FindById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(1), "title": "second"}); !reflect.DeepEqual(gotResource, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, gotResource)
+	}
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// compareValues compares two json decoded values and returns -1, 0 or +1. Values of
+// the same type are compared naturally, while values of different types are ordered
+// by type as null < bool < number < string < array < object.
+func compareValues(a, b interface{}) int {
+	if rankA, rankB := typeRank(a), typeRank(b); rankA != rankB {
+		if rankA < rankB {
+			return -1
+		}
+
+		return 1
+	}
+
+	if x, ok := toNumber(a); ok {
+		y, _ := toNumber(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	case nil:
+		return 0
+	default:
+		// Arrays and objects have no natural order, so compare their json representation.
+		return strings.Compare(FormatValue(a), FormatValue(b))
+	}
+}
+
+// typeRank returns the position of the value type in the ordering of different types.
+func typeRank(value interface{}) int {
+	if _, ok := toNumber(value); ok {
+		return 2
+	}
+
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case string:
+		return 3
+	case []interface{}:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// toNumber converts a numeric value to float64. Besides json decoded numbers, also
+// integers are supported, as resources may be populated with them from code.
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,327 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errInvalidCell = errors.New("invalid cell")
+
+// Column types of csv files, declared in the header row after the field name, e.g. 'price:number'.
+const (
+	columnString = "string"
+	columnNumber = "number"
+	columnBool   = "bool"
+	columnJSON   = "json"
+)
+
+// csvColumn is a column of a csv file, as declared in the header row.
+type csvColumn struct {
+	field string
+	// kind is the declared type of the column, or empty if it's inferred from its cells.
+	kind string
+}
+
+// header returns the header cell of the column.
+func (c csvColumn) header() string {
+	if c.kind == "" {
+		return c.field
+	}
+
+	return c.field + ":" + c.kind
+}
+
+// parseCSVHeader returns the columns of the header row. Cells may declare the type of the column after
+// the field name, e.g. 'price:number', while any other suffix is part of the field name, e.g. 'time:utc'.
+func parseCSVHeader(header []string) []csvColumn {
+	columns := make([]csvColumn, 0, len(header))
+	for _, cell := range header {
+		column := csvColumn{field: cell}
+
+		if idx := strings.LastIndex(cell, ":"); idx != -1 {
+			switch kind := cell[idx+1:]; kind {
+			case columnString, columnNumber, columnBool, columnJSON:
+				column = csvColumn{field: cell[:idx], kind: kind}
+			}
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+// decodeCSV returns the resources of the rows, where the header row contains the field names. Empty
+// cells are omitted, while the type of columns without a declared one is inferred from their cells.
+func decodeCSV(contentBytes []byte) (interface{}, error) {
+	records, err := csv.NewReader(bytes.NewReader(contentBytes)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]interface{}, 0)
+	if len(records) == 0 {
+		return resources, nil
+	}
+
+	columns := parseCSVHeader(records[0])
+
+	rows := records[1:]
+	for idx, column := range columns {
+		if column.kind == "" {
+			columns[idx].kind = inferColumnType(rows, idx)
+		}
+	}
+
+	for _, row := range rows {
+		resource := make(map[string]interface{}, len(columns))
+		for idx, column := range columns {
+			if idx >= len(row) || row[idx] == "" {
+				continue
+			}
+
+			value, err := decodeCell(row[idx], column.kind)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s of column %s", err, row[idx], column.field)
+			}
+
+			resource[column.field] = value
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// inferColumnType returns the type of the column, which is the one all its non empty cells have. Cells
+// are inferred as numbers only if they are written back unchanged, so that e.g. '01234' stays a string.
+func inferColumnType(rows [][]string, idx int) string {
+	if emptyColumn(rows, idx) {
+		return columnString
+	}
+
+	kinds := []string{columnNumber, columnBool, columnJSON}
+
+	for _, kind := range kinds {
+		matches := true
+		for _, row := range rows {
+			if idx >= len(row) || row[idx] == "" {
+				continue
+			}
+
+			value, err := decodeCell(row[idx], kind)
+			if err != nil {
+				matches = false
+				break
+			}
+
+			if number, ok := value.(float64); ok && strconv.FormatFloat(number, 'f', -1, 64) != row[idx] {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return kind
+		}
+	}
+
+	return columnString
+}
+
+// emptyColumn reports whether all the cells of the column are empty.
+func emptyColumn(rows [][]string, idx int) bool {
+	for _, row := range rows {
+		if idx < len(row) && row[idx] != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeCell returns the value of the cell, by the type of its column.
+func decodeCell(cell, kind string) (interface{}, error) {
+	switch kind {
+	case columnNumber:
+		number, err := strconv.ParseFloat(cell, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, errInvalidCell
+		}
+
+		return number, nil
+	case columnBool:
+		switch cell {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		default:
+			return nil, errInvalidCell
+		}
+	case columnJSON:
+		// Only arrays and objects are inferred as json, so other cells are kept as strings.
+		if trimmed := strings.TrimSpace(cell); !strings.HasPrefix(trimmed, "[") && !strings.HasPrefix(trimmed, "{") {
+			return nil, errInvalidCell
+		}
+
+		value, err := decodeJSON([]byte(cell))
+		if err != nil {
+			return nil, errInvalidCell
+		}
+
+		return value, nil
+	default:
+		return cell, nil
+	}
+}
+
+// encodeCSV returns the rows of the resources. The header row of the previous contents is kept, so
+// that columns keep their order and declared types, while new fields are appended in alphabetical order.
+// Types of columns are declared once inferred, from the previous cells or else the new ones, so that
+// changed cells can't change the type of a whole column. Values of another type are rejected.
+func encodeCSV(value interface{}, previous []byte, _ map[string]string) ([]byte, error) {
+	value, err := normalize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, ok := value.([]interface{})
+	if !ok {
+		return nil, errResourceInvalidType
+	}
+
+	var columns []csvColumn
+	if records, err := csv.NewReader(bytes.NewReader(previous)).ReadAll(); err == nil && len(records) > 0 {
+		columns = parseCSVHeader(records[0])
+
+		rows := records[1:]
+		for idx, column := range columns {
+			if column.kind == "" && !emptyColumn(rows, idx) {
+				columns[idx].kind = inferColumnType(rows, idx)
+			}
+		}
+	}
+
+	existing := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		existing[column.field] = true
+	}
+
+	newFields := make([]string, 0)
+	for _, resource := range resources {
+		fields, ok := resource.(map[string]interface{})
+		if !ok {
+			return nil, errResourceInvalidType
+		}
+
+		for field := range fields {
+			if !existing[field] {
+				existing[field] = true
+				newFields = append(newFields, field)
+			}
+		}
+	}
+
+	sort.Strings(newFields)
+
+	for _, field := range newFields {
+		columns = append(columns, csvColumn{field: field})
+	}
+
+	rows := make([][]string, 0, len(resources))
+	for _, resource := range resources {
+		fields := resource.(map[string]interface{})
+
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			cell, err := encodeCell(fields[column.field])
+			if err != nil {
+				return nil, err
+			}
+
+			row = append(row, cell)
+		}
+
+		rows = append(rows, row)
+	}
+
+	for idx, column := range columns {
+		if column.kind == "" && !emptyColumn(rows, idx) {
+			columns[idx].kind = inferColumnType(rows, idx)
+		}
+	}
+
+	if err := checkCSVCells(columns, resources, rows); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.header())
+	}
+
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// checkCSVCells checks that the cells of the rows are decoded to the values of the resources, by the type
+// of their column, e.g. that a string isn't written to a number column.
+func checkCSVCells(columns []csvColumn, resources []interface{}, rows [][]string) error {
+	for rowIdx, row := range rows {
+		fields := resources[rowIdx].(map[string]interface{})
+
+		for idx, column := range columns {
+			if row[idx] == "" {
+				continue
+			}
+
+			value, err := decodeCell(row[idx], column.kind)
+			if err != nil || !reflect.DeepEqual(value, fields[column.field]) {
+				return fmt.Errorf("%w: invalid value %s of column %s", ErrBadRequest, row[idx], column.header())
+			}
+		}
+	}
+
+	return nil
+}
+
+// encodeCell returns the cell of the value, where arrays and objects are json encoded.
+func encodeCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+
+		return string(valueBytes), nil
+	}
+}
@@ -0,0 +1,301 @@
+package storage_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestReadContent_CSV(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected []interface{}
+	}{
+		{
+			name:    "inferred types",
+			content: "id,title,price,available,tags,zip\n1,first,9.5,true,\"[\"\"a\"\"]\",01234\n2,second,,false,[],x1\n",
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1), "title": "first", "price": 9.5, "available": true, "tags": []interface{}{"a"}, "zip": "01234"},
+				map[string]interface{}{"id": float64(2), "title": "second", "available": false, "tags": []interface{}{}, "zip": "x1"},
+			},
+		},
+		{
+			name:    "declared types",
+			content: "id:string,count:number,meta:json,flag:bool\n1,2,\"{\"\"a\"\":1}\",true\n",
+			expected: []interface{}{
+				map[string]interface{}{"id": "1", "count": float64(2), "meta": map[string]interface{}{"a": float64(1)}, "flag": true},
+			},
+		},
+		{
+			name:    "unknown type suffix as part of field name",
+			content: "id,time:utc,count:number\n1,10:00,2\n",
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1), "time:utc": "10:00", "count": float64(2)},
+			},
+		},
+		{
+			name:     "header only",
+			content:  "id,title\n",
+			expected: []interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			filename := filepath.Join(dir, "books.csv")
+			if err = ioutil.WriteFile(filename, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := storage.ReadContent(filename, "books")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected content %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadContent_CSVInvalidCell(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "books.csv")
+	if err = ioutil.WriteFile(filename, []byte("id,price:number\n1,cheap\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storage.ReadContent(filename, "books"); err == nil {
+		t.Fatalf("expected error for invalid cell, but got %v", err)
+	}
+}
+
+func TestFileKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		expected string
+	}{
+		{name: "csv", filename: filepath.Join("fixtures", "books.csv"), expected: "books"},
+		{name: "json", filename: "db.json", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := storage.FileKey(tc.filename); got != tc.expected {
+				t.Fatalf("expected key %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFile_CSV(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "books.csv")
+	if err = ioutil.WriteFile(filename, []byte("title,id,price:number\nfirst,1,10\nsecond,2,20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewResourceFile(filename, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storageSvc.FindById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(1), "title": "first", "price": float64(10)}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	if _, err = storageSvc.Update("1", storage.Resource{"price": 12.5, "tags": []interface{}{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storageSvc.Create(storage.Resource{"title": "third, with comma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = storageSvc.Delete("2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storageSvc.FindById("2"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Columns keep their order and types, which are declared once inferred, while new ones are appended.
+	expected := "title:string,id:number,price:number,tags:json\nfirst,1,12.5,\"[\"\"a\"\",\"\"b\"\"]\"\n\"third, with comma\",3,,\n"
+	if got := string(contentBytes); got != expected {
+		t.Fatalf("expected content %q, but got %q", expected, got)
+	}
+
+	got, err = storageSvc.FindById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(1), "title": "first", "price": 12.5, "tags": []interface{}{"a", "b"}}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+}
+
+func TestFile_CSVLeadingZeros(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "customers.csv")
+	content := "id,zip,phone,price\n1,01234,0030210,1.50\n2,10115,0049301,2\n"
+	if err = ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewResourceFile(filename, "customers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storageSvc.FindById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(1), "zip": "01234", "phone": "0030210", "price": "1.50"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	// An unrelated change rewrites the file, which keeps the cells unchanged.
+	if _, err = storageSvc.Create(storage.Resource{"zip": "00501"}); err != nil {
+		t.Fatal(err)
+	}
+
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id:number,zip:string,phone:string,price:string\n1,01234,0030210,1.50\n2,10115,0049301,2\n3,00501,,\n"
+	if got := string(contentBytes); got != expected {
+		t.Fatalf("expected content %q, but got %q", expected, got)
+	}
+}
+
+func TestFile_CSVColumnTypes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		update   storage.Resource
+		err      error
+		expected string
+	}{
+		{
+			name:     "Value of inferred type",
+			content:  "id,price,tags\n1,10,[]\n2,20,\n",
+			update:   storage.Resource{"price": float64(12)},
+			expected: "id:number,price:number,tags:json\n1,12,[]\n2,20,\n",
+		},
+		{
+			name:     "Value of other type than inferred",
+			content:  "id,price\n1,10\n2,20\n",
+			update:   storage.Resource{"price": "cheap"},
+			err:      storage.ErrBadRequest,
+			expected: "id,price\n1,10\n2,20\n",
+		},
+		{
+			name:     "Value of other type than declared",
+			content:  "id,available:bool\n1,true\n2,false\n",
+			update:   storage.Resource{"available": "yes"},
+			err:      storage.ErrBadRequest,
+			expected: "id,available:bool\n1,true\n2,false\n",
+		},
+		{
+			name:     "Number value of string column",
+			content:  "id,zip\n1,01234\n2,10115\n",
+			update:   storage.Resource{"zip": float64(10117)},
+			err:      storage.ErrBadRequest,
+			expected: "id,zip\n1,01234\n2,10115\n",
+		},
+		{
+			name:     "Values of empty and new columns",
+			content:  "id,time:utc,note\n1,,\n2,,\n",
+			update:   storage.Resource{"time:utc": "10:00", "views": float64(3)},
+			expected: "id:number,time:utc:string,note,views:number\n1,10:00,,3\n2,,,\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			filename := filepath.Join(dir, "products.csv")
+			if err = ioutil.WriteFile(filename, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			storageSvc, err := storage.NewResourceFile(filename, "products")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = storageSvc.Update("1", tc.update); !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, but got %v", tc.err, err)
+			}
+
+			contentBytes, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := string(contentBytes); got != tc.expected {
+				t.Fatalf("expected content %q, but got %q", tc.expected, got)
+			}
+
+			// Cells of other resources keep their type.
+			got, err := storageSvc.FindById("2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := got["id"].(float64); !ok {
+				t.Fatalf("expected id of type %T, but got %T", float64(0), got["id"])
+			}
+		})
+	}
+}
@@ -4,28 +4,70 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
-	"math/rand"
-	"strconv"
+	"os"
+	"path/filepath"
+	"sync"
 )
 
 var (
 	errResourceInvalidType = errors.New("invalid resource type")
 )
 
+// fileLocks contains a lock per file, shared by all the storage instances of the same file.
+var fileLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.RWMutex
+}{locks: make(map[string]*sync.RWMutex)}
+
 // File implements the storage interface, and uses a file as 'database'.
 type File struct {
 	filename string
 	key      string
+	// fileKey is the key of the only resources of the file, if it doesn't contain the ones of every key.
+	fileKey string
+	mu      *sync.RWMutex
+	options
+}
+
+// NewFile returns a new file instance, for a file which contains the resources of every key.
+func NewFile(filename, key string, opts ...Option) (*File, error) {
+	mu, err := fileLock(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{filename: filename, key: key, mu: mu, options: newOptions(opts)}, nil
+}
+
+// NewResourceFile returns a new file instance, for a file which contains only the resources of the
+// key, e.g. 'books.json' with the array of 'books'.
+func NewResourceFile(filename, key string, opts ...Option) (*File, error) {
+	f, err := NewFile(filename, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	f.fileKey = key
+
+	return f, nil
 }
 
-// NewFile returns a new file instance.
-func NewFile(filename, key string) (*File, error) {
-	return &File{filename: filename, key: key}, nil
+// IDField returns the name of the id field of resources.
+func (f *File) IDField() string {
+	return f.idField
 }
 
-// Find all resources for the specific key.
-func (f *File) Find() ([]Resource, error) {
-	data, err := readFile(f.filename)
+// idFields returns the id field of the resources by key, to match them with the ones of the file.
+func (f *File) idFields() map[string]string {
+	return map[string]string{f.key: f.idField}
+}
+
+// Find all resources for the specific key, that match the provided filter.
+func (f *File) Find(filter Filter) ([]Resource, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	data, _, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return nil, err
 	}
@@ -34,12 +76,15 @@ func (f *File) Find() ([]Resource, error) {
 		return nil, ErrResourceNotFound
 	}
 
-	return data[f.key], nil
+	return filterResources(data[f.key], filter), nil
 }
 
 // FindById a resource for the specific key.
 func (f *File) FindById(id string) (Resource, error) {
-	data, err := readFile(f.filename)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	data, _, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return nil, err
 	}
@@ -48,18 +93,15 @@ func (f *File) FindById(id string) (Resource, error) {
 		return nil, ErrResourceNotFound
 	}
 
-	for _, resource := range data[f.key] {
-		if resource["id"] == id {
-			return resource, nil
-		}
-	}
-
-	return nil, ErrResourceNotFound
+	return findById(data[f.key], f.idField, id)
 }
 
 // Create a new resource for the specific key.
 func (f *File) Create(newResource Resource) (Resource, error) {
-	data, err := readFile(f.filename)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, singular, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return nil, err
 	}
@@ -68,12 +110,17 @@ func (f *File) Create(newResource Resource) (Resource, error) {
 		return nil, ErrResourceNotFound
 	}
 
-	_, ok := newResource["id"]
+	_, ok := newResource[f.idField]
 	if !ok {
-		newResource["id"] = generateNewId(data[f.key])
+		id, err := f.idGenerator.Generate(resourceIDs(data[f.key], f.idField))
+		if err != nil {
+			return nil, err
+		}
+
+		newResource[f.idField] = id
 	} else {
 		for _, resource := range data[f.key] {
-			if resource["id"] == newResource["id"] {
+			if FormatID(resource[f.idField]) == FormatID(newResource[f.idField]) {
 				return nil, ErrResourceAlreadyExists
 			}
 		}
@@ -82,7 +129,7 @@ func (f *File) Create(newResource Resource) (Resource, error) {
 	newData := append(data[f.key], newResource)
 	data[f.key] = newData
 
-	if err := updateFile(f.filename, data); err != nil {
+	if err := updateFile(f.filename, f.fileKey, data, singular, f.idFields()); err != nil {
 		return nil, err
 	}
 
@@ -91,7 +138,10 @@ func (f *File) Create(newResource Resource) (Resource, error) {
 
 // Replace an existing resource for the specific key.
 func (f *File) Replace(id string, replaced Resource) (Resource, error) {
-	data, err := readFile(f.filename)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, singular, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return nil, err
 	}
@@ -101,15 +151,17 @@ func (f *File) Replace(id string, replaced Resource) (Resource, error) {
 	}
 
 	// Check if resource with the requested id exists.
-	if _, err = f.FindById(id); err != nil {
+	current, err := findById(data[f.key], f.idField, id)
+	if err != nil {
 		return nil, err
 	}
 
-	replaced["id"] = id
+	// Keep the id, along with its original type.
+	replaced[f.idField] = current[f.idField]
 
 	newResources := make([]Resource, 0)
 	for _, d := range data[f.key] {
-		if d["id"] == id {
+		if MatchID(d[f.idField], id) {
 			newResources = append(newResources, replaced)
 		} else {
 			newResources = append(newResources, d)
@@ -118,7 +170,7 @@ func (f *File) Replace(id string, replaced Resource) (Resource, error) {
 
 	data[f.key] = newResources
 
-	if err := updateFile(f.filename, data); err != nil {
+	if err := updateFile(f.filename, f.fileKey, data, singular, f.idFields()); err != nil {
 		return nil, err
 	}
 
@@ -127,7 +179,10 @@ func (f *File) Replace(id string, replaced Resource) (Resource, error) {
 
 // Update an existing resource for the specific key.
 func (f *File) Update(id string, updatedReq Resource) (Resource, error) {
-	data, err := readFile(f.filename)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, singular, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return nil, err
 	}
@@ -137,21 +192,24 @@ func (f *File) Update(id string, updatedReq Resource) (Resource, error) {
 	}
 
 	// Check if resource with the requested id exists and retrieve it.
-	updated, err := f.FindById(id)
+	updated, err := findById(data[f.key], f.idField, id)
 	if err != nil {
 		return nil, err
 	}
 
+	currentID := updated[f.idField]
+
 	// Apply any changes to current resource.
 	for key, val := range updatedReq {
 		updated[key] = val
 	}
 
-	updated["id"] = id
+	// Keep the id, along with its original type.
+	updated[f.idField] = currentID
 
 	newResources := make([]Resource, 0)
 	for _, d := range data[f.key] {
-		if d["id"] == id {
+		if MatchID(d[f.idField], id) {
 			newResources = append(newResources, updated)
 		} else {
 			newResources = append(newResources, d)
@@ -160,7 +218,7 @@ func (f *File) Update(id string, updatedReq Resource) (Resource, error) {
 
 	data[f.key] = newResources
 
-	if err := updateFile(f.filename, data); err != nil {
+	if err := updateFile(f.filename, f.fileKey, data, singular, f.idFields()); err != nil {
 		return nil, err
 	}
 
@@ -169,7 +227,10 @@ func (f *File) Update(id string, updatedReq Resource) (Resource, error) {
 
 // Delete an existing resource for the specific key.
 func (f *File) Delete(id string) error {
-	data, err := readFile(f.filename)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, singular, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return err
 	}
@@ -179,13 +240,13 @@ func (f *File) Delete(id string) error {
 	}
 
 	// Check if resource with the requested id exists.
-	if _, err = f.FindById(id); err != nil {
+	if _, err = findById(data[f.key], f.idField, id); err != nil {
 		return err
 	}
 
 	newResources := make([]Resource, 0)
 	for _, d := range data[f.key] {
-		if d["id"] == id {
+		if MatchID(d[f.idField], id) {
 			continue
 		}
 
@@ -194,82 +255,285 @@ func (f *File) Delete(id string) error {
 
 	data[f.key] = newResources
 
-	return updateFile(f.filename, data)
+	return updateFile(f.filename, f.fileKey, data, singular, f.idFields())
 }
 
 // DB returns all resources.
 func (f *File) DB() (Database, error) {
-	return readFile(f.filename)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	data, _, err := readFile(f.filename, f.fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
 }
 
-// readFile returns all the data from the watch file.
-func readFile(file string) (Database, error) {
-	contentBytes, err := ioutil.ReadFile(file)
+// SingularFile implements the singular storage interface, and uses a file as 'database'.
+type SingularFile struct {
+	filename string
+	key      string
+	// fileKey is the key of the only resource of the file, if it doesn't contain the ones of every key.
+	fileKey string
+	mu      *sync.RWMutex
+}
+
+// NewSingularFile returns a new singular file instance, for a file which contains the resources of every key.
+func NewSingularFile(filename, key string) (*SingularFile, error) {
+	mu, err := fileLock(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SingularFile{filename: filename, key: key, mu: mu}, nil
+}
+
+// NewSingularResourceFile returns a new singular file instance, for a file which contains only the
+// resource of the key, e.g. 'profile.json' with the object of 'profile'.
+func NewSingularResourceFile(filename, key string) (*SingularFile, error) {
+	f, err := NewSingularFile(filename, key)
+	if err != nil {
+		return nil, err
+	}
+
+	f.fileKey = key
+
+	return f, nil
+}
+
+// Get the singular resource for the specific key.
+func (f *SingularFile) Get() (Resource, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	_, singular, err := readFile(f.filename, f.fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, ok := singular[f.key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	return resource, nil
+}
+
+// Replace the singular resource for the specific key.
+func (f *SingularFile) Replace(replaced Resource) (Resource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, singular, err := readFile(f.filename, f.fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := singular[f.key]; !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	singular[f.key] = replaced
+
+	if err := updateFile(f.filename, f.fileKey, data, singular, nil); err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// Update the singular resource for the specific key.
+func (f *SingularFile) Update(updatedReq Resource) (Resource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, singular, err := readFile(f.filename, f.fileKey)
 	if err != nil {
 		return nil, err
 	}
 
-	content := make(map[string]interface{})
-	if err = json.Unmarshal(contentBytes, &content); err != nil {
+	updated, ok := singular[f.key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	// Apply any changes to current resource.
+	for key, val := range updatedReq {
+		updated[key] = val
+	}
+
+	if err := updateFile(f.filename, f.fileKey, data, singular, nil); err != nil {
 		return nil, err
 	}
 
+	return updated, nil
+}
+
+// readFile returns all the data from the watch file, decoded by the format of its extension. Array
+// values contain the resources of each key, while object values are singular resources. If fileKey is
+// not empty, the file contains only the value of that key.
+func readFile(file, fileKey string) (Database, map[string]Resource, error) {
+	contentBytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeContent(formatOf(file), contentBytes, fileKey)
+}
+
+// decodeContent returns the resources of each key and the singular resources, from file contents
+// of the format. If fileKey is not empty, the contents are only the value of that key.
+func decodeContent(format fileFormat, contentBytes []byte, fileKey string) (Database, map[string]Resource, error) {
+	value, err := decodeFormat(format, contentBytes, fileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return contentResources(value, fileKey)
+}
+
+// contentResources returns the resources of each key and the singular resources, of json compatible
+// contents. If fileKey is not empty, the contents are only the value of that key.
+func contentResources(value interface{}, fileKey string) (Database, map[string]Resource, error) {
+	content, ok := value.(map[string]interface{})
+	if fileKey != "" {
+		content = map[string]interface{}{fileKey: value}
+	} else if !ok {
+		return nil, nil, errResourceInvalidType
+	}
+
 	database := make(Database)
+	singular := make(map[string]Resource)
 	for key, val := range content {
-		valResources, ok := val.([]interface{})
-		if !ok {
-			return nil, errResourceInvalidType
+		switch v := val.(type) {
+		case []interface{}:
+			data := make([]Resource, 0)
+			for _, resource := range v {
+				resourceBytes, err := json.Marshal(resource)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				var newResource Resource
+				if err := json.Unmarshal(resourceBytes, &newResource); err != nil {
+					return nil, nil, err
+				}
+
+				data = append(data, newResource)
+			}
+
+			database[key] = data
+		case map[string]interface{}:
+			singular[key] = v
+		default:
+			return nil, nil, errResourceInvalidType
 		}
+	}
 
-		data := make([]Resource, 0)
-		for _, resource := range valResources {
-			resourceBytes, err := json.Marshal(resource)
-			if err != nil {
-				return nil, err
-			}
+	return database, singular, nil
+}
 
-			var newResource Resource
-			if err := json.Unmarshal(resourceBytes, &newResource); err != nil {
-				return nil, err
-			}
+// updateFile formats and writes the new data, along with the singular resources, to the watch file.
+// If fileKey is not empty, only the value of that key is written. Resources are matched with the
+// current ones by the id fields of their keys, where the format preserves comments.
+func updateFile(file, fileKey string, data Database, singular map[string]Resource, idFields map[string]string) error {
+	contentBytes, err := encodeFile(file, fileKey, data, singular, idFields)
+	if err != nil {
+		return err
+	}
 
-			data = append(data, newResource)
-		}
+	return writeFile(file, contentBytes)
+}
 
-		database[key] = data
+// encodeFile formats the data, along with the singular resources, as contents of the watch file, by
+// the format of its extension. If fileKey is not empty, the contents are only the value of that key.
+func encodeFile(file, fileKey string, data Database, singular map[string]Resource, idFields map[string]string) ([]byte, error) {
+	// Current contents are used to preserve key order and comments, where the format supports it.
+	previous, _ := ioutil.ReadFile(file)
+
+	return encodeContent(formatOf(file), data, singular, previous, fileKey, idFields)
+}
+
+// encodeContent formats the data, along with the singular resources, as file contents of the format.
+// If fileKey is not empty, the contents are only the value of that key.
+func encodeContent(format fileFormat, data Database, singular map[string]Resource, previous []byte, fileKey string, idFields map[string]string) ([]byte, error) {
+	content := make(map[string]interface{}, len(data)+len(singular))
+	for key, resources := range data {
+		content[key] = resources
+	}
+
+	for key, resource := range singular {
+		content[key] = resource
 	}
 
-	return database, nil
+	if fileKey != "" {
+		return encodeFormat(format, content[fileKey], previous, fileKey, idFields)
+	}
+
+	return encodeFormat(format, content, previous, fileKey, idFields)
 }
 
-// updateFile formats and writes the new data to the watch file.
-func updateFile(file string, content Database) error {
-	contentBytes, err := json.MarshalIndent(content, "", "  ")
+// writeFile writes the contents to the watch file. Contents are written to a temporary file first,
+// so the watch file is replaced atomically.
+func writeFile(file string, contentBytes []byte) error {
+	// Write to a temporary file first, so the watch file is replaced atomically.
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
 
-	if err = ioutil.WriteFile(file, contentBytes, 0644); err != nil {
+	if _, err = tmp.Write(contentBytes); err != nil {
+		tmp.Close()
 		return err
 	}
 
-	return nil
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
 }
 
-// generateNewId and validate that is unique across provided data.
-func generateNewId(data []Resource) string {
-	existingIds := make(map[string]bool)
-	for _, d := range data {
-		existingIds[d["id"].(string)] = true
+// fileLock returns the lock of the file, shared by all the storage instances of the same file.
+func fileLock(filename string) (*sync.RWMutex, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fileLocks.Lock()
+	defer fileLocks.Unlock()
+
+	mu, ok := fileLocks.locks[path]
+	if !ok {
+		mu = &sync.RWMutex{}
+		fileLocks.locks[path] = mu
 	}
 
-	for {
-		newId := strconv.Itoa(rand.Intn(1000))
+	return mu, nil
+}
 
-		if !existingIds[newId] {
-			return newId
+// findById a resource among the provided ones, by the value of their id field.
+func findById(resources []Resource, idField, id string) (Resource, error) {
+	for _, resource := range resources {
+		if MatchID(resource[idField], id) {
+			return resource, nil
 		}
 	}
+
+	return nil, ErrResourceNotFound
 }
 
 // checkResourceKeyExists in the file data.
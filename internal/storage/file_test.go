@@ -7,8 +7,10 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,15 +38,20 @@ func TestFind(t *testing.T) {
 	randomKeyIndex := rand.Intn(len(keys))
 	randomKey := keys[randomKeyIndex]
 
+	randomResourceIndex := rand.Intn(len(testData[randomKey]))
+	randomResource := testData[randomKey][randomResourceIndex]
+
 	type args struct {
 		key      string
 		filename string
 	}
 	testCases := []struct {
-		name    string
-		args    args
-		wantErr bool
-		err     error
+		name         string
+		args         args
+		filter       storage.Filter
+		expectedData []storage.Resource
+		wantErr      bool
+		err          error
 	}{
 		{
 			name: "List all resources of specific key",
@@ -52,6 +59,25 @@ func TestFind(t *testing.T) {
 				key:      randomKey,
 				filename: f.Name(),
 			},
+			expectedData: testData[randomKey],
+		},
+		{
+			name: "List filtered resources of specific key",
+			args: args{
+				key:      randomKey,
+				filename: f.Name(),
+			},
+			filter:       storage.Filter{{Field: "field_1", Values: []string{randomResource["field_1"].(string)}}},
+			expectedData: []storage.Resource{randomResource},
+		},
+		{
+			name: "List filtered resources of specific key without match",
+			args: args{
+				key:      randomKey,
+				filename: f.Name(),
+			},
+			filter:       storage.Filter{{Field: "field_1", Values: []string{"randomValue"}}},
+			expectedData: []storage.Resource{},
 		},
 		{
 			name: "List all resources of invalid key",
@@ -79,18 +105,18 @@ func TestFind(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		got, err := storageSvc.Find()
+		got, err := storageSvc.Find(tt.filter)
 		if err != nil && !tt.wantErr {
 			t.Fatal(err)
 		}
 
 		if !tt.wantErr {
-			if len(got) != len(testData[tt.args.key]) {
-				t.Fatalf("expected data length %v, but got %v", len(testData[tt.args.key]), len(got))
+			if len(got) != len(tt.expectedData) {
+				t.Fatalf("expected data length %v, but got %v", len(tt.expectedData), len(got))
 			}
 
-			if !reflect.DeepEqual(got, testData[tt.args.key]) {
-				t.Fatalf("expected data %v, but got %v", testData[tt.args.key], got)
+			if !reflect.DeepEqual(got, tt.expectedData) {
+				t.Fatalf("expected data %v, but got %v", tt.expectedData, got)
 			}
 		} else {
 			if err == nil || !errors.Is(err, tt.err) {
@@ -288,7 +314,7 @@ func TestCreate(t *testing.T) {
 				t.Fatalf("expected created %v, but got %v", tt.resource, got)
 			}
 
-			currData, err := storageSvc.Find()
+			currData, err := storageSvc.Find(nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -414,7 +440,7 @@ func TestReplace(t *testing.T) {
 				t.Fatalf("expected replaced %v, but got %v", tt.resource, got)
 			}
 
-			currData, err := storageSvc.Find()
+			currData, err := storageSvc.Find(nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -540,7 +566,7 @@ func TestUpdate(t *testing.T) {
 				}
 			}
 
-			currData, err := storageSvc.Find()
+			currData, err := storageSvc.Find(nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -637,7 +663,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		if !tt.wantErr {
-			currData, err := storageSvc.Find()
+			currData, err := storageSvc.Find(nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -712,6 +738,422 @@ func TestDB(t *testing.T) {
 	}
 }
 
+func TestFile_ConcurrentCreate(t *testing.T) {
+	f, err := testGenerateStorageFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	const creates = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, creates)
+
+	for idx := 0; idx < creates; idx++ {
+		wg.Add(1)
+
+		go func(idx int) {
+			defer wg.Done()
+
+			// Every instance must share the lock of the file.
+			storageSvc, err := storage.NewFile(f.Name(), keys[idx%len(keys)])
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			_, err = storageSvc.Create(storage.Resource{"id": fmt.Sprintf("concurrent-%d", idx)})
+			errs <- err
+		}(idx)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	storageSvc, err := storage.NewFile(f.Name(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storageSvc.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx := 0; idx < creates; idx++ {
+		key := keys[idx%len(keys)]
+		id := fmt.Sprintf("concurrent-%d", idx)
+
+		found := false
+		for _, resource := range got[key] {
+			if resource["id"] == id {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Fatalf("expected resource %v of key %v to be created", id, key)
+		}
+	}
+
+	if expected := len(testData[keys[0]]) + len(testData[keys[1]]) + creates; len(got[keys[0]])+len(got[keys[1]]) != expected {
+		t.Fatalf("expected %v resources, but got %v", expected, len(got[keys[0]])+len(got[keys[1]]))
+	}
+}
+
+func TestFile_TypedIDs(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	content := `{"posts": [{"id": 1, "title": "numeric"}, {"id": [1, "a"], "title": "composite"}, {"id": "x", "title": "string"}]}`
+	if err = ioutil.WriteFile(f.Name(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewFile(f.Name(), "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storageSvc.FindById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(1), "title": "numeric"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	got, err = storageSvc.Update("1", storage.Resource{"id": "2", "title": "updated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(1), "title": "updated"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	got, err = storageSvc.Replace("1,a", storage.Resource{"title": "replaced"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": []interface{}{float64(1), "a"}, "title": "replaced"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	if _, err = storageSvc.Create(storage.Resource{"id": "1", "title": "duplicate"}); !errors.Is(err, storage.ErrResourceAlreadyExists) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceAlreadyExists, err)
+	}
+
+	if err = storageSvc.Delete("1,a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = storageSvc.Delete("x"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Generated ids follow the type of the existing ids.
+	got, err = storageSvc.Create(storage.Resource{"title": "created"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(2), "title": "created"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	if _, err = storageSvc.FindById("2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_IDField(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err = ioutil.WriteFile(f.Name(), []byte(`{"products": [{"sku": "a1", "id": "other"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewFile(f.Name(), "products", storage.WithIDField("sku"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := storageSvc.IDField(); got != "sku" {
+		t.Fatalf("expected id field %v, but got %v", "sku", got)
+	}
+
+	if _, err = storageSvc.FindById("other"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	got, err := storageSvc.Replace("a1", storage.Resource{"sku": "b2", "name": "keyboard"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"sku": "a1", "name": "keyboard"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+
+	got, err = storageSvc.Create(storage.Resource{"name": "mouse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := got["sku"]; !ok {
+		t.Fatalf("expected generated id field %v, but got %v", "sku", got)
+	}
+
+	if _, ok := got["id"]; ok {
+		t.Fatalf("expected no default id field, but got %v", got)
+	}
+}
+
+func TestResourceFile(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	booksFile := filepath.Join(dir, "books.json")
+	if err = ioutil.WriteFile(booksFile, []byte(`[{"id": 1, "title": "json-server"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	profileFile := filepath.Join(dir, "profile.json")
+	if err = ioutil.WriteFile(profileFile, []byte(`{"name": "typicode"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	books, err := storage.NewResourceFile(booksFile, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = books.Create(storage.Resource{"title": "json-server in go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := storage.NewSingularResourceFile(profileFile, "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = profile.Update(storage.Resource{"age": float64(30)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Files must contain only the value of their key.
+	testCases := []struct {
+		name     string
+		filename string
+		expected interface{}
+	}{
+		{
+			name:     "Resources",
+			filename: booksFile,
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1), "title": "json-server"},
+				map[string]interface{}{"id": float64(2), "title": "json-server in go"},
+			},
+		},
+		{
+			name:     "Singular resource",
+			filename: profileFile,
+			expected: map[string]interface{}{"name": "typicode", "age": float64(30)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentBytes, err := ioutil.ReadFile(tc.filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got interface{}
+			if err = json.Unmarshal(contentBytes, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected file content %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+
+	// Merged storage must return the resources of every file.
+	booksDB, err := storage.NewResourceFile(booksFile, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	posts, err := storage.NewMock(storage.Database{"posts": {{"id": "1"}}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := storage.NewMerged(booksDB, posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := merged.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := storage.Database{
+		"books": {
+			{"id": float64(1), "title": "json-server"},
+			{"id": float64(2), "title": "json-server in go"},
+		},
+		"posts": {{"id": "1"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+}
+
+func TestSingularFile(t *testing.T) {
+	f, err := testGenerateStorageFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	content := map[string]interface{}{"profile": storage.Resource{"name": "typicode", "age": float64(30)}}
+	for key, resources := range testData {
+		content[key] = resources
+	}
+
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(f.Name(), contentBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		key          string
+		method       string
+		resource     storage.Resource
+		expectedData storage.Resource
+		wantErr      bool
+		err          error
+	}{
+		{
+			name:         "Get singular resource",
+			key:          "profile",
+			method:       "get",
+			expectedData: storage.Resource{"name": "typicode", "age": float64(30)},
+		},
+		{
+			name:         "Update singular resource",
+			key:          "profile",
+			method:       "update",
+			resource:     storage.Resource{"age": float64(31)},
+			expectedData: storage.Resource{"name": "typicode", "age": float64(31)},
+		},
+		{
+			name:         "Replace singular resource",
+			key:          "profile",
+			method:       "replace",
+			resource:     storage.Resource{"name": "chanioxaris"},
+			expectedData: storage.Resource{"name": "chanioxaris"},
+		},
+		{
+			name:         "Get singular resource after changes",
+			key:          "profile",
+			method:       "get",
+			expectedData: storage.Resource{"name": "chanioxaris"},
+		},
+		{
+			name:    "Get singular resource of not existing key",
+			key:     "randomKey",
+			method:  "get",
+			wantErr: true,
+			err:     storage.ErrResourceNotFound,
+		},
+		{
+			name:     "Update singular resource of not existing key",
+			key:      "randomKey",
+			method:   "update",
+			resource: storage.Resource{"age": float64(31)},
+			wantErr:  true,
+			err:      storage.ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range testCases {
+		singularSvc, err := storage.NewSingularFile(f.Name(), tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got storage.Resource
+		switch tt.method {
+		case "update":
+			got, err = singularSvc.Update(tt.resource)
+		case "replace":
+			got, err = singularSvc.Replace(tt.resource)
+		default:
+			got, err = singularSvc.Get()
+		}
+
+		if !tt.wantErr {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedData) {
+				t.Fatalf("%s: expected data %v, but got %v", tt.name, tt.expectedData, got)
+			}
+		} else {
+			if err == nil || !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, but got %v", tt.err, err)
+			}
+		}
+	}
+
+	// Resources of the rest keys must be kept intact.
+	storageSvc, err := storage.NewFile(f.Name(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storageSvc.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, storage.Database(testData)) {
+		t.Fatalf("expected data %v, but got %v", testData, got)
+	}
+}
+
 func testGenerateStorageFile() (*os.File, error) {
 	f, err := ioutil.TempFile(".", "")
 	if err != nil {
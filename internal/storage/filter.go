@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operator defines how the values of a condition are compared with a resource field.
+type Operator string
+
+const (
+	// OperatorEq matches fields equal to any of the values.
+	OperatorEq Operator = "eq"
+	// OperatorNe matches fields not equal to all of the values.
+	OperatorNe Operator = "ne"
+	// OperatorGte matches fields greater than or equal to any of the values.
+	OperatorGte Operator = "gte"
+	// OperatorLte matches fields less than or equal to any of the values.
+	OperatorLte Operator = "lte"
+	// OperatorLike matches fields against any of the values as case insensitive regular expressions.
+	OperatorLike Operator = "like"
+	// OperatorSearch matches fields containing, in any of their nested string values, any of the
+	// values case insensitively. An empty field searches the whole resource.
+	OperatorSearch Operator = "search"
+)
+
+// Condition represents a single criterion that a resource field must satisfy.
+type Condition struct {
+	// Field is the dot separated path of the field, e.g. 'author.name'.
+	Field string
+	// Operator used to compare the field with values. Defaults to OperatorEq.
+	Operator Operator
+	// Values the field is compared with.
+	Values []string
+
+	patterns []*regexp.Regexp
+}
+
+// Filter represents a set of conditions that all must be satisfied by a resource.
+type Filter []Condition
+
+// NewCondition returns a new condition, after validating its values against the operator.
+func NewCondition(field string, operator Operator, values []string) (Condition, error) {
+	condition := Condition{Field: field, Operator: operator, Values: values}
+
+	switch operator {
+	case "", OperatorEq, OperatorNe, OperatorGte, OperatorLte, OperatorSearch:
+	case OperatorLike:
+		for _, value := range values {
+			pattern, err := regexp.Compile("(?i)" + value)
+			if err != nil {
+				return Condition{}, fmt.Errorf("%w: invalid pattern %q", ErrBadRequest, value)
+			}
+
+			condition.patterns = append(condition.patterns, pattern)
+		}
+	default:
+		return Condition{}, fmt.Errorf("%w: unsupported operator %q", ErrBadRequest, operator)
+	}
+
+	return condition, nil
+}
+
+// Match reports whether the resource satisfies every condition of the filter.
+func (f Filter) Match(resource Resource) bool {
+	for _, condition := range f {
+		if !condition.match(resource) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Condition) match(resource Resource) bool {
+	if c.Operator == OperatorSearch && c.Field == "" {
+		return SearchHits(resource, c.Values) > 0
+	}
+
+	value, ok := lookup(resource, c.Field)
+
+	// A missing field is never equal to any value.
+	if c.Operator == OperatorNe {
+		return !ok || !c.matchAny(value, OperatorEq)
+	}
+
+	if !ok {
+		return false
+	}
+
+	return c.matchAny(value, c.Operator)
+}
+
+// matchAny reports whether value satisfies the operator for any of the condition values.
+func (c Condition) matchAny(value interface{}, operator Operator) bool {
+	formatted := FormatValue(value)
+
+	for idx, v := range c.Values {
+		switch operator {
+		case OperatorGte:
+			if cmp, ok := compareQueryValue(value, v); ok && cmp >= 0 {
+				return true
+			}
+		case OperatorLte:
+			if cmp, ok := compareQueryValue(value, v); ok && cmp <= 0 {
+				return true
+			}
+		case OperatorLike:
+			if c.pattern(idx).MatchString(formatted) {
+				return true
+			}
+		case OperatorSearch:
+			if searchHits(value, strings.ToLower(v)) > 0 {
+				return true
+			}
+		default:
+			if formatted == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// pattern returns the compiled regular expression of the value in index. Conditions
+// created without NewCondition are compiled on demand, treating invalid ones literally.
+func (c Condition) pattern(idx int) *regexp.Regexp {
+	if idx < len(c.patterns) {
+		return c.patterns[idx]
+	}
+
+	pattern, err := regexp.Compile("(?i)" + c.Values[idx])
+	if err != nil {
+		return regexp.MustCompile("(?i)" + regexp.QuoteMeta(c.Values[idx]))
+	}
+
+	return pattern
+}
+
+// SearchHits returns the number of occurrences of the terms in all string values of resource,
+// including the ones of nested objects and arrays. Terms are matched case insensitively.
+func SearchHits(resource Resource, terms []string) int {
+	hits := 0
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+
+		hits += searchHits(map[string]interface{}(resource), strings.ToLower(term))
+	}
+
+	return hits
+}
+
+// searchHits returns the number of occurrences of a lower case term in the string values of value.
+func searchHits(value interface{}, term string) int {
+	switch v := value.(type) {
+	case string:
+		return strings.Count(strings.ToLower(v), term)
+	case map[string]interface{}:
+		hits := 0
+		for _, val := range v {
+			hits += searchHits(val, term)
+		}
+
+		return hits
+	case Resource:
+		return searchHits(map[string]interface{}(v), term)
+	case []interface{}:
+		hits := 0
+		for _, val := range v {
+			hits += searchHits(val, term)
+		}
+
+		return hits
+	default:
+		return 0
+	}
+}
+
+// filterResources returns the resources that match the provided filter.
+func filterResources(resources []Resource, filter Filter) []Resource {
+	if len(filter) == 0 {
+		return resources
+	}
+
+	filtered := make([]Resource, 0)
+	for _, resource := range resources {
+		if filter.Match(resource) {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	return filtered
+}
+
+// lookup the value of a dot separated path in resource. Array elements can be
+// accessed by their index, e.g. 'tags.0'.
+func lookup(resource Resource, path string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(resource)
+
+	for _, part := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			val, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+
+			current = val
+		case Resource:
+			val, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+
+			current = val
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// compareQueryValue compares a json decoded value with a query string value, by first
+// converting the latter to the type of the former. Reports false if they are not comparable.
+func compareQueryValue(value interface{}, query string) (int, bool) {
+	if _, ok := toNumber(value); ok {
+		number, err := strconv.ParseFloat(query, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return compareValues(value, number), true
+	}
+
+	switch v := value.(type) {
+	case string:
+		return compareValues(v, query), true
+	case bool:
+		boolean, err := strconv.ParseBool(query)
+		if err != nil {
+			return 0, false
+		}
+
+		return compareValues(v, boolean), true
+	default:
+		return 0, false
+	}
+}
+
+// FormatValue returns the string representation of a json decoded value, as
+// it would appear in a query string or a url path.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	default:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+
+		return string(valueBytes)
+	}
+}
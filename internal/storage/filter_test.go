@@ -0,0 +1,249 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestFilter_Match(t *testing.T) {
+	resource := storage.Resource{
+		"id":        "1",
+		"title":     "Clean Code",
+		"published": float64(2008),
+		"available": true,
+		"author": map[string]interface{}{
+			"name": "Robert Martin",
+		},
+		"tags": []interface{}{"programming", "craftsmanship"},
+	}
+
+	testCases := []struct {
+		name     string
+		filter   storage.Filter
+		expected bool
+	}{
+		{
+			name:     "Empty filter",
+			filter:   nil,
+			expected: true,
+		},
+		{
+			name:     "Match string field",
+			filter:   storage.Filter{{Field: "title", Values: []string{"Clean Code"}}},
+			expected: true,
+		},
+		{
+			name:     "Match number field",
+			filter:   storage.Filter{{Field: "published", Values: []string{"2008"}}},
+			expected: true,
+		},
+		{
+			name:     "Match bool field",
+			filter:   storage.Filter{{Field: "available", Values: []string{"true"}}},
+			expected: true,
+		},
+		{
+			name:     "Match nested field",
+			filter:   storage.Filter{{Field: "author.name", Values: []string{"Robert Martin"}}},
+			expected: true,
+		},
+		{
+			name:     "Match array element",
+			filter:   storage.Filter{{Field: "tags.1", Values: []string{"craftsmanship"}}},
+			expected: true,
+		},
+		{
+			name:     "Match any of repeated values",
+			filter:   storage.Filter{{Field: "published", Values: []string{"1866", "2008"}}},
+			expected: true,
+		},
+		{
+			name: "Match all conditions",
+			filter: storage.Filter{
+				{Field: "title", Values: []string{"Clean Code"}},
+				{Field: "author.name", Values: []string{"Robert Martin"}},
+			},
+			expected: true,
+		},
+		{
+			name: "Not match one of conditions",
+			filter: storage.Filter{
+				{Field: "title", Values: []string{"Clean Code"}},
+				{Field: "author.name", Values: []string{"Fyodor Dostoevsky"}},
+			},
+			expected: false,
+		},
+		{
+			name:     "Not match missing field",
+			filter:   storage.Filter{{Field: "author.age", Values: []string{"50"}}},
+			expected: false,
+		},
+		{
+			name:     "Not match out of range array element",
+			filter:   storage.Filter{{Field: "tags.5", Values: []string{"programming"}}},
+			expected: false,
+		},
+		{
+			name:     "Match not equal field",
+			filter:   storage.Filter{{Field: "title", Operator: storage.OperatorNe, Values: []string{"foo", "bar"}}},
+			expected: true,
+		},
+		{
+			name:     "Match not equal missing field",
+			filter:   storage.Filter{{Field: "subtitle", Operator: storage.OperatorNe, Values: []string{"foo"}}},
+			expected: true,
+		},
+		{
+			name:     "Not match not equal field",
+			filter:   storage.Filter{{Field: "title", Operator: storage.OperatorNe, Values: []string{"foo", "Clean Code"}}},
+			expected: false,
+		},
+		{
+			name: "Match number range",
+			filter: storage.Filter{
+				{Field: "published", Operator: storage.OperatorGte, Values: []string{"1900"}},
+				{Field: "published", Operator: storage.OperatorLte, Values: []string{"2008"}},
+			},
+			expected: true,
+		},
+		{
+			name:     "Not match number range compared numerically",
+			filter:   storage.Filter{{Field: "published", Operator: storage.OperatorGte, Values: []string{"10000"}}},
+			expected: false,
+		},
+		{
+			name:     "Not match number range with non numeric value",
+			filter:   storage.Filter{{Field: "published", Operator: storage.OperatorLte, Values: []string{"abc"}}},
+			expected: false,
+		},
+		{
+			name:     "Match string range",
+			filter:   storage.Filter{{Field: "title", Operator: storage.OperatorLte, Values: []string{"D"}}},
+			expected: true,
+		},
+		{
+			name:     "Match pattern case insensitive",
+			filter:   storage.Filter{{Field: "title", Operator: storage.OperatorLike, Values: []string{"^clean"}}},
+			expected: true,
+		},
+		{
+			name:     "Match search in any field",
+			filter:   storage.Filter{{Operator: storage.OperatorSearch, Values: []string{"foo", "MARTIN"}}},
+			expected: true,
+		},
+		{
+			name:     "Match search in array field",
+			filter:   storage.Filter{{Operator: storage.OperatorSearch, Values: []string{"craft"}}},
+			expected: true,
+		},
+		{
+			name:     "Match search in specific field",
+			filter:   storage.Filter{{Field: "author", Operator: storage.OperatorSearch, Values: []string{"robert"}}},
+			expected: true,
+		},
+		{
+			name:     "Not match search in non string values",
+			filter:   storage.Filter{{Operator: storage.OperatorSearch, Values: []string{"2008"}}},
+			expected: false,
+		},
+		{
+			name:     "Not match pattern",
+			filter:   storage.Filter{{Field: "author.name", Operator: storage.OperatorLike, Values: []string{"^Martin"}}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		if got := tt.filter.Match(resource); got != tt.expected {
+			t.Fatalf("%s: expected match %v, but got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestNewCondition(t *testing.T) {
+	testCases := []struct {
+		name     string
+		operator storage.Operator
+		values   []string
+		wantErr  bool
+		err      error
+	}{
+		{
+			name:     "Equal condition",
+			operator: storage.OperatorEq,
+			values:   []string{"value"},
+		},
+		{
+			name:     "Pattern condition",
+			operator: storage.OperatorLike,
+			values:   []string{"^val.*e$"},
+		},
+		{
+			name:     "Pattern condition with invalid pattern",
+			operator: storage.OperatorLike,
+			values:   []string{"^val.*e$", "[a-"},
+			wantErr:  true,
+			err:      storage.ErrBadRequest,
+		},
+		{
+			name:     "Condition with unsupported operator",
+			operator: storage.Operator("gt"),
+			values:   []string{"value"},
+			wantErr:  true,
+			err:      storage.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		_, err := storage.NewCondition("field", tt.operator, tt.values)
+		if err != nil && !tt.wantErr {
+			t.Fatal(err)
+		}
+
+		if tt.wantErr && (err == nil || !errors.Is(err, tt.err)) {
+			t.Fatalf("expected error %v, but got %v", tt.err, err)
+		}
+	}
+}
+
+func TestSearchHits(t *testing.T) {
+	resource := storage.Resource{
+		"id":    "1",
+		"title": "Code Complete",
+		"author": map[string]interface{}{
+			"name": "Steve McConnell",
+		},
+		"tags":      []interface{}{"code", map[string]interface{}{"name": "construction"}},
+		"published": float64(1993),
+	}
+
+	testCases := []struct {
+		name     string
+		terms    []string
+		expected int
+	}{
+		{
+			name:     "Hits in nested values",
+			terms:    []string{"code"},
+			expected: 2,
+		},
+		{
+			name:     "Hits of multiple terms",
+			terms:    []string{"CODE", "con", "missing"},
+			expected: 4,
+		},
+		{
+			name:     "Hits of empty term",
+			terms:    []string{""},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		if got := storage.SearchHits(resource, tt.terms); got != tt.expected {
+			t.Fatalf("%s: expected hits %v, but got %v", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/titanous/json5"
+	"gopkg.in/yaml.v3"
+)
+
+// fileFormat decodes and encodes the contents of files of a specific format.
+type fileFormat struct {
+	// decode returns the json compatible value of the contents.
+	decode func(contentBytes []byte) (interface{}, error)
+	// encode returns the contents of a json compatible value. Previous contents of the file are used
+	// to preserve key order and comments, where the format supports it, while idFields contains the id
+	// fields of the resources by key, to match them with the previous ones. The key is empty, if the
+	// value is the resources of a single key.
+	encode func(value interface{}, previous []byte, idFields map[string]string) ([]byte, error)
+	// tableOnly formats can't contain top level arrays, so a file with the resources of a single key
+	// contains them in a table under that key, e.g. '[[books]]'.
+	tableOnly bool
+	// resourceOnly formats can only contain the resources of a single key, which is named after the file,
+	// e.g. 'books.csv'.
+	resourceOnly bool
+}
+
+// fileFormats contains the supported formats, by file extension.
+var fileFormats = map[string]fileFormat{
+	".json":  {decode: decodeJSON, encode: encodeJSON},
+	".json5": {decode: decodeJSON5, encode: encodeJSON5},
+	".yaml":  {decode: decodeYAML, encode: encodeYAML},
+	".yml":   {decode: decodeYAML, encode: encodeYAML},
+	".toml":  {decode: decodeTOML, encode: encodeTOML, tableOnly: true},
+	".csv":   {decode: decodeCSV, encode: encodeCSV, resourceOnly: true},
+}
+
+// SupportedFile reports whether the format of the file is supported, by its extension.
+func SupportedFile(filename string) bool {
+	_, ok := fileFormats[strings.ToLower(filepath.Ext(filename))]
+	return ok
+}
+
+// FileKey returns the key of the resources of the file, if its format can only contain the ones of a
+// single key, which is named after the file, e.g. 'books' for 'books.csv'. Otherwise, it's empty.
+func FileKey(filename string) string {
+	if !formatOf(filename).resourceOnly {
+		return ""
+	}
+
+	base := filepath.Base(filename)
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// formatOf returns the format of the file by its extension, which defaults to json.
+func formatOf(filename string) fileFormat {
+	if format, ok := fileFormats[strings.ToLower(filepath.Ext(filename))]; ok {
+		return format
+	}
+
+	return fileFormats[".json"]
+}
+
+// ReadContent returns the json compatible contents of the file, decoded by the format of its extension.
+// If fileKey is not empty, the file contains only the value of that key, which is returned.
+func ReadContent(filename, fileKey string) (interface{}, error) {
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFormat(formatOf(filename), contentBytes, fileKey)
+}
+
+// WriteContent writes the json compatible contents to the file, encoded by the format of its extension.
+// If fileKey is not empty, the contents are only the value of that key.
+func WriteContent(filename, fileKey string, content interface{}) error {
+	contentBytes, err := encodeFormat(formatOf(filename), content, nil, fileKey, nil)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filename, contentBytes)
+}
+
+// decodeFormat returns the json compatible contents, decoded by the format. If fileKey is not empty,
+// the contents are only the value of that key, which is returned.
+func decodeFormat(format fileFormat, contentBytes []byte, fileKey string) (interface{}, error) {
+	value, err := format.decode(contentBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileKey == "" || !format.tableOnly {
+		return value, nil
+	}
+
+	content, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errResourceInvalidType
+	}
+
+	return content[fileKey], nil
+}
+
+// encodeFormat returns the contents of the json compatible value, encoded by the format. If fileKey is
+// not empty, the value is only the one of that key. Resources are matched with the previous ones by
+// the id fields of their keys.
+func encodeFormat(format fileFormat, value interface{}, previous []byte, fileKey string, idFields map[string]string) ([]byte, error) {
+	if fileKey != "" && format.tableOnly {
+		value = map[string]interface{}{fileKey: value}
+	} else if fileKey != "" {
+		idFields = map[string]string{"": idFields[fileKey]}
+	}
+
+	return format.encode(value, previous, idFields)
+}
+
+// normalize returns the json compatible form of a decoded value, e.g. with numbers as float64.
+func normalize(value interface{}) (interface{}, error) {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSON(valueBytes)
+}
+
+func decodeJSON(contentBytes []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(contentBytes, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func encodeJSON(value interface{}, _ []byte, _ map[string]string) ([]byte, error) {
+	return json.MarshalIndent(value, "", "  ")
+}
+
+// decodeJSON5 allows comments, trailing commas, unquoted keys and single quoted strings.
+func decodeJSON5(contentBytes []byte) (interface{}, error) {
+	var value interface{}
+	if err := json5.Unmarshal(contentBytes, &value); err != nil {
+		return nil, err
+	}
+
+	return normalize(value)
+}
+
+// encodeJSON5 encodes the value as json, which is valid json5, as comments and key order of the previous
+// contents can't be preserved.
+func encodeJSON5(value interface{}, previous []byte, idFields map[string]string) ([]byte, error) {
+	return encodeJSON(value, previous, idFields)
+}
+
+func decodeYAML(contentBytes []byte) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(contentBytes, &value); err != nil {
+		return nil, err
+	}
+
+	return normalize(value)
+}
+
+// encodeYAML merges the value into the previous contents, so that existing keys keep their order
+// and comments. New keys are appended in alphabetical order.
+func encodeYAML(value interface{}, previous []byte, idFields map[string]string) ([]byte, error) {
+	value, err := normalize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(previous, &doc); err != nil || doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{}}}
+	}
+
+	if err := mergeYAMLNode(doc.Content[0], value, idFields[""], idFields); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// mergeYAMLNode sets the value to the node, keeping the order and comments of any existing keys and
+// array elements. Elements of an array are matched with the existing ones by idField, if not empty, or
+// by position otherwise, while fieldIDFields contains the id fields of the arrays of an object, by key.
+func mergeYAMLNode(node *yaml.Node, value interface{}, idField string, fieldIDFields map[string]string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if node.Kind != yaml.MappingNode {
+			return replaceYAMLNode(node, value)
+		}
+
+		content := make([]*yaml.Node, 0, 2*len(v))
+		merged := make(map[string]bool, len(v))
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			key, val := node.Content[idx], node.Content[idx+1]
+
+			fieldValue, ok := v[key.Value]
+			if !ok {
+				continue
+			}
+
+			if err := mergeYAMLNode(val, fieldValue, fieldIDFields[key.Value], nil); err != nil {
+				return err
+			}
+
+			merged[key.Value] = true
+			content = append(content, key, val)
+		}
+
+		newKeys := make([]string, 0)
+		for key := range v {
+			if !merged[key] {
+				newKeys = append(newKeys, key)
+			}
+		}
+
+		sort.Strings(newKeys)
+
+		for _, key := range newKeys {
+			val := &yaml.Node{}
+			if err := val.Encode(v[key]); err != nil {
+				return err
+			}
+
+			content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, val)
+		}
+
+		node.Content = content
+	case []interface{}:
+		if node.Kind != yaml.SequenceNode {
+			return replaceYAMLNode(node, value)
+		}
+
+		byID := yamlNodesByID(node.Content, idField)
+
+		content := make([]*yaml.Node, 0, len(v))
+		for idx, item := range v {
+			var existing *yaml.Node
+			if idField == "" && idx < len(node.Content) {
+				existing = node.Content[idx]
+			} else if fields, ok := item.(map[string]interface{}); ok && idField != "" && fields[idField] != nil {
+				existing = byID[FormatID(fields[idField])]
+				// Every existing element is matched once, even with duplicate ids.
+				delete(byID, FormatID(fields[idField]))
+			}
+
+			if existing == nil {
+				val := &yaml.Node{}
+				if err := val.Encode(item); err != nil {
+					return err
+				}
+
+				content = append(content, val)
+				continue
+			}
+
+			if err := mergeYAMLNode(existing, item, "", nil); err != nil {
+				return err
+			}
+
+			content = append(content, existing)
+		}
+
+		node.Content = content
+	default:
+		var current interface{}
+		if node.Kind == yaml.ScalarNode && node.Decode(&current) == nil {
+			// Keep unchanged values as is, e.g. with their quotes.
+			if normalized, err := normalize(current); err == nil && normalized == value {
+				return nil
+			}
+		}
+
+		return replaceYAMLNode(node, value)
+	}
+
+	return nil
+}
+
+// yamlNodesByID returns the object nodes of an array, by the formatted value of their id field.
+func yamlNodesByID(nodes []*yaml.Node, idField string) map[string]*yaml.Node {
+	byID := make(map[string]*yaml.Node)
+	if idField == "" {
+		return byID
+	}
+
+	for _, node := range nodes {
+		if node.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			if node.Content[idx].Value != idField {
+				continue
+			}
+
+			var id interface{}
+			if err := node.Content[idx+1].Decode(&id); err != nil {
+				break
+			}
+
+			if id, err := normalize(id); err == nil {
+				if _, ok := byID[FormatID(id)]; !ok {
+					byID[FormatID(id)] = node
+				}
+			}
+
+			break
+		}
+	}
+
+	return byID
+}
+
+// replaceYAMLNode replaces the node with the one of the value, keeping its comments.
+func replaceYAMLNode(node *yaml.Node, value interface{}) error {
+	newNode := yaml.Node{}
+	if err := newNode.Encode(value); err != nil {
+		return err
+	}
+
+	newNode.HeadComment = node.HeadComment
+	newNode.LineComment = node.LineComment
+	newNode.FootComment = node.FootComment
+
+	*node = newNode
+
+	return nil
+}
+
+func decodeTOML(contentBytes []byte) (interface{}, error) {
+	var value map[string]interface{}
+	if err := toml.Unmarshal(contentBytes, &value); err != nil {
+		return nil, err
+	}
+
+	return normalize(value)
+}
+
+// encodeTOML encodes keys in alphabetical order, as comments and key order can't be preserved.
+func encodeTOML(value interface{}, _ []byte, _ map[string]string) ([]byte, error) {
+	value, err := normalize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	encoder.Indent = ""
+
+	if err := encoder.Encode(tomlIntegers(value)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// tomlIntegers returns the value with whole numbers as integers, since toml distinguishes them from
+// floats, e.g. '1' instead of '1.0'.
+func tomlIntegers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			converted[key] = tomlIntegers(val)
+		}
+
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for idx, val := range v {
+			converted[idx] = tomlIntegers(val)
+		}
+
+		return converted
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,320 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestSupportedFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		expected bool
+	}{
+		{name: "json", filename: "db.json", expected: true},
+		{name: "json5", filename: "db.json5", expected: true},
+		{name: "yaml", filename: "db.yaml", expected: true},
+		{name: "yml", filename: "db.YML", expected: true},
+		{name: "toml", filename: "db.toml", expected: true},
+		{name: "unsupported", filename: "db.xml", expected: false},
+		{name: "no extension", filename: "db", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := storage.SupportedFile(tc.filename); got != tc.expected {
+				t.Fatalf("expected supported %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadContent(t *testing.T) {
+	expected := map[string]interface{}{
+		"posts":   []interface{}{map[string]interface{}{"id": float64(1), "title": "first"}},
+		"profile": map[string]interface{}{"name": "typicode"},
+	}
+
+	testCases := []struct {
+		name     string
+		filename string
+		content  string
+	}{
+		{
+			name:     "json",
+			filename: "db.json",
+			content:  `{"posts": [{"id": 1, "title": "first"}], "profile": {"name": "typicode"}}`,
+		},
+		{
+			name:     "json5",
+			filename: "db.json5",
+			content: `{
+  // Comments and trailing commas are allowed.
+  posts: [{id: 1, title: 'first',},],
+  profile: {name: "typicode"},
+}`,
+		},
+		{
+			name:     "yaml",
+			filename: "db.yaml",
+			content: `posts:
+  - id: 1
+    title: first
+profile:
+  name: typicode
+`,
+		},
+		{
+			name:     "toml",
+			filename: "db.toml",
+			content: `[[posts]]
+id = 1
+title = "first"
+
+[profile]
+name = "typicode"
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			filename := filepath.Join(dir, tc.filename)
+			if err = ioutil.WriteFile(filename, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := storage.ReadContent(filename, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected content %v, but got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestFile_YAMLPreservesComments(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "db.yaml")
+	content := `# Posts of the blog.
+posts:
+  - title: first # The first post.
+    id: 1
+`
+	if err = ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewFile(filename, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storageSvc.Update("1", storage.Resource{"title": "updated"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storageSvc.Create(storage.Resource{"title": "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Posts of the blog.
+posts:
+  - title: updated # The first post.
+    id: 1
+  - id: 2
+    title: second
+`
+	if got := string(contentBytes); got != expected {
+		t.Fatalf("expected content %q, but got %q", expected, got)
+	}
+}
+
+func TestFile_TOMLResourceFile(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "books.toml")
+	if err = ioutil.WriteFile(filename, []byte("[[books]]\nid = 1\ntitle = \"first\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewResourceFile(filename, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storageSvc.Create(storage.Resource{"title": "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storage.ReadContent(filename, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"id": float64(1), "title": "first"},
+		map[string]interface{}{"id": float64(2), "title": "second"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected content %v, but got %v", expected, got)
+	}
+
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedContent := "[[books]]\nid = 1\ntitle = \"first\"\n\n[[books]]\nid = 2\ntitle = \"second\"\n"
+	if got := string(contentBytes); got != expectedContent {
+		t.Fatalf("expected content %q, but got %q", expectedContent, got)
+	}
+}
+
+func TestFile_YAMLMatchesRecordsByID(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "db.yaml")
+	content := `posts:
+  # The first post.
+  - id: 1
+    title: first
+  # The second post.
+  - id: 2
+    title: second # Keep the title.
+`
+	if err = ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	storageSvc, err := storage.NewFile(filename, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = storageSvc.Delete("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `posts:
+  # The second post.
+  - id: 2
+    title: second # Keep the title.
+`
+	if got := string(contentBytes); got != expected {
+		t.Fatalf("expected content %q, but got %q", expected, got)
+	}
+}
+
+func TestFile_JSON5Writes(t *testing.T) {
+	content := `{
+  // Posts of the blog.
+  posts: [{id: 1, title: 'first',},],
+}`
+
+	testCases := []struct {
+		name       string
+		newStorage func(filename string) (storage.Storage, func() error, error)
+	}{
+		{
+			name: "File",
+			newStorage: func(filename string) (storage.Storage, func() error, error) {
+				storageSvc, err := storage.NewFile(filename, "posts")
+				return storageSvc, func() error { return nil }, err
+			},
+		},
+		{
+			name: "Memory",
+			newStorage: func(filename string) (storage.Storage, func() error, error) {
+				doc, err := storage.NewMemoryDocument(filename, time.Hour)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				storageSvc, err := storage.NewMemory(doc, "posts")
+				return storageSvc, doc.Close, err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			filename := filepath.Join(dir, "db.json5")
+			if err = ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			storageSvc, closeStorage, err := tc.newStorage(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = storageSvc.Create(storage.Resource{"title": "second"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = closeStorage(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := storage.ReadContent(filename, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := map[string]interface{}{
+				"posts": []interface{}{
+					map[string]interface{}{"id": float64(1), "title": "first"},
+					map[string]interface{}{"id": float64(2), "title": "second"},
+				},
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected content %v, but got %v", expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errUnsupportedIDGenerator = errors.New("unsupported id generator")
+	errFailedGenerateID       = errors.New("failed to generate unique id")
+)
+
+const (
+	// IDGeneratorAutoIncrement generates sequential integer ids, following the greatest existing one.
+	IDGeneratorAutoIncrement = "autoincrement"
+	// IDGeneratorUUID generates random (version 4) UUIDs.
+	IDGeneratorUUID = "uuid"
+	// IDGeneratorULID generates lexicographically sortable ULIDs.
+	IDGeneratorULID = "ulid"
+	// IDGeneratorNanoID generates random 21 characters url friendly ids.
+	IDGeneratorNanoID = "nanoid"
+
+	// maxGenerateAttempts limits the attempts to generate an id not already taken.
+	maxGenerateAttempts = 10
+)
+
+// FormatID returns the normalized string form of an id, so that ids of any json type can be matched
+// with the ones of request paths. Numbers and strings are formatted as is, e.g. 1 as '1', while
+// composite ids are formatted as their comma separated values, e.g. [1, "a"] as '1,a'.
+func FormatID(value interface{}) string {
+	if values, ok := value.([]interface{}); ok {
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			parts = append(parts, FormatID(v))
+		}
+
+		return strings.Join(parts, ",")
+	}
+
+	return FormatValue(value)
+}
+
+// MatchID reports whether the id value of a resource matches the requested id.
+func MatchID(value interface{}, id string) bool {
+	return FormatID(value) == id
+}
+
+// placeholders of id templates, mapped to the generators of their values.
+var placeholders = map[string]func() (string, error){
+	"uuid":   newUUID,
+	"ulid":   newULID,
+	"nanoid": newNanoID,
+}
+
+// placeholderRegex matches the placeholders of id templates, e.g. '{seq}'.
+var placeholderRegex = regexp.MustCompile(`{(\w+)}`)
+
+// IDGenerator generates new ids, that are unique among the existing resources of a key.
+type IDGenerator interface {
+	Generate(ids []interface{}) (interface{}, error)
+}
+
+// resourceIDs returns the values of the id field of resources.
+func resourceIDs(resources []Resource, idField string) []interface{} {
+	ids := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		ids = append(ids, resource[idField])
+	}
+
+	return ids
+}
+
+// templateGenerator generates ids based on a template, where placeholders are replaced by generated
+// values, e.g. 'usr_{seq}'. Supported placeholders are '{seq}', '{uuid}', '{ulid}' and '{nanoid}'.
+type templateGenerator struct {
+	template string
+	// seqRegex matches ids of the template, capturing the sequence value.
+	seqRegex *regexp.Regexp
+}
+
+// NewIDGenerator returns a new id generator for the strategy, which is either one of the predefined
+// generators ('autoincrement', 'uuid', 'ulid', 'nanoid') or a template like 'usr_{seq}'.
+func NewIDGenerator(strategy string) (IDGenerator, error) {
+	switch strategy {
+	case IDGeneratorAutoIncrement:
+		strategy = "{seq}"
+	case IDGeneratorUUID, IDGeneratorULID, IDGeneratorNanoID:
+		strategy = "{" + strategy + "}"
+	}
+
+	matches := placeholderRegex.FindAllStringSubmatchIndex(strategy, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedIDGenerator, strategy)
+	}
+
+	g := &templateGenerator{template: strategy}
+
+	// Build the expression that matches ids of the template, if it contains a sequence.
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	seq, last := 0, 0
+	for _, match := range matches {
+		pattern.WriteString(regexp.QuoteMeta(strategy[last:match[0]]))
+		last = match[1]
+
+		name := strategy[match[2]:match[3]]
+		switch _, ok := placeholders[name]; {
+		case name == "seq":
+			seq++
+			pattern.WriteString(`(\d+)`)
+		case ok:
+			pattern.WriteString(`.+?`)
+		default:
+			return nil, fmt.Errorf("%w: unknown placeholder %q", errUnsupportedIDGenerator, name)
+		}
+	}
+
+	pattern.WriteString(regexp.QuoteMeta(strategy[last:]))
+	pattern.WriteString("$")
+
+	if seq > 1 {
+		return nil, fmt.Errorf("%w: multiple sequences in %q", errUnsupportedIDGenerator, strategy)
+	}
+
+	if seq == 1 {
+		g.seqRegex = regexp.MustCompile(pattern.String())
+	}
+
+	return g, nil
+}
+
+// Generate a new id, that is unique among the existing ids. Sequential ids are numbers, unless any of the
+// existing ids isn't a number, e.g. a string, in which case ids are strings.
+func (g *templateGenerator) Generate(ids []interface{}) (interface{}, error) {
+	existingIds := make(map[string]bool, len(ids))
+	numeric := true
+	for _, id := range ids {
+		existingIds[FormatID(id)] = true
+
+		if _, ok := id.(float64); !ok {
+			numeric = false
+		}
+	}
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		id, err := g.generate(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		if existingIds[id] {
+			continue
+		}
+
+		if numeric && g.template == "{seq}" {
+			return strconv.ParseFloat(id, 64)
+		}
+
+		return id, nil
+	}
+
+	return nil, errFailedGenerateID
+}
+
+func (g *templateGenerator) generate(ids []interface{}) (string, error) {
+	var genErr error
+
+	id := placeholderRegex.ReplaceAllStringFunc(g.template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		if name == "seq" {
+			return strconv.FormatInt(g.nextSeq(ids), 10)
+		}
+
+		value, err := placeholders[name]()
+		if err != nil {
+			genErr = err
+		}
+
+		return value
+	})
+
+	if genErr != nil {
+		return "", genErr
+	}
+
+	return id, nil
+}
+
+// nextSeq returns the value following the greatest sequence among the ids of the template.
+func (g *templateGenerator) nextSeq(ids []interface{}) int64 {
+	var maxSeq int64
+	for _, id := range ids {
+		match := g.seqRegex.FindStringSubmatch(FormatID(id))
+		if match == nil {
+			continue
+		}
+
+		if seq, err := strconv.ParseInt(match[1], 10, 64); err == nil && seq > maxSeq {
+			maxSeq = seq
+		}
+	}
+
+	return maxSeq + 1
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// Set version 4 and RFC 4122 variant bits.
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// crockfordAlphabet is the base32 alphabet of ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID, made of a 48 bit millisecond timestamp followed by 80 random bits.
+func newULID() (string, error) {
+	b := make([]byte, 16)
+
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, uint64(time.Now().UnixNano()/int64(time.Millisecond)))
+	copy(b[:6], timestamp[2:])
+
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	// Encode the 128 bits as 26 base32 characters, 5 bits each, the first one using only 3 bits.
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	ulid := make([]byte, 26)
+	for idx := 25; idx >= 0; idx-- {
+		ulid[idx] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(ulid), nil
+}
+
+// nanoIDAlphabet is the url friendly alphabet of nanoids.
+const nanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// newNanoID returns a random 21 characters nanoid.
+func newNanoID() (string, error) {
+	b := make([]byte, 21)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// The alphabet has 64 characters, so every random byte maps uniformly to one of them.
+	for idx := range b {
+		b[idx] = nanoIDAlphabet[b[idx]&0x3f]
+	}
+
+	return string(b), nil
+}
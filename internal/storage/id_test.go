@@ -0,0 +1,137 @@
+package storage_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestIDGenerator(t *testing.T) {
+	ids := []interface{}{"1", float64(7), "usr_3", "usr_12", "random"}
+
+	testCases := []struct {
+		name     string
+		strategy string
+		expected *regexp.Regexp
+	}{
+		{name: "Auto increment", strategy: "autoincrement", expected: regexp.MustCompile(`^8$`)},
+		{name: "UUID", strategy: "uuid", expected: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{name: "ULID", strategy: "ulid", expected: regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)},
+		{name: "Nanoid", strategy: "nanoid", expected: regexp.MustCompile(`^[\w-]{21}$`)},
+		{name: "Template with sequence", strategy: "usr_{seq}", expected: regexp.MustCompile(`^usr_13$`)},
+		{name: "Template with sequence of no existing ids", strategy: "ord-{seq}-x", expected: regexp.MustCompile(`^ord-1-x$`)},
+		{name: "Template with uuid", strategy: "doc_{uuid}", expected: regexp.MustCompile(`^doc_[0-9a-f-]{36}$`)},
+	}
+
+	for _, tt := range testCases {
+		idGenerator, err := storage.NewIDGenerator(tt.strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := idGenerator.Generate(ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := got.(string); !ok {
+			t.Fatalf("%s: expected string id, but got %T", tt.name, got)
+		}
+
+		if !tt.expected.MatchString(storage.FormatID(got)) {
+			t.Fatalf("%s: expected id matching %v, but got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestIDGenerator_Numeric(t *testing.T) {
+	idGenerator, err := storage.NewIDGenerator("autoincrement")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		ids      []interface{}
+		expected interface{}
+	}{
+		{name: "Numeric ids", ids: []interface{}{float64(1), float64(4)}, expected: float64(5)},
+		{name: "Empty collection", ids: []interface{}{}, expected: float64(1)},
+		{name: "String ids", ids: []interface{}{"1", "4"}, expected: "5"},
+		{name: "Mixed ids", ids: []interface{}{float64(1), "4"}, expected: "5"},
+	}
+
+	for _, tt := range testCases {
+		got, err := idGenerator.Generate(tt.ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("%s: expected id %v (%T), but got %v (%T)", tt.name, tt.expected, tt.expected, got, got)
+		}
+	}
+}
+
+func TestFormatID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		id       interface{}
+		expected string
+	}{
+		{name: "String id", id: "abc", expected: "abc"},
+		{name: "Integer id", id: float64(12), expected: "12"},
+		{name: "Decimal id", id: float64(1.5), expected: "1.5"},
+		{name: "Composite id", id: []interface{}{float64(1), "a"}, expected: "1,a"},
+	}
+
+	for _, tt := range testCases {
+		if got := storage.FormatID(tt.id); got != tt.expected {
+			t.Fatalf("%s: expected id %v, but got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestIDGenerator_Unique(t *testing.T) {
+	idGenerator, err := storage.NewIDGenerator("nanoid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]interface{}, 0)
+	for idx := 0; idx < 2000; idx++ {
+		id, err := idGenerator.Generate(ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	seen := make(map[interface{}]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("expected unique ids, but got duplicate %v", id)
+		}
+
+		seen[id] = true
+	}
+}
+
+func TestNewIDGenerator(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy string
+	}{
+		{name: "Unknown strategy", strategy: "random"},
+		{name: "Unknown placeholder", strategy: "usr_{random}"},
+		{name: "Multiple sequences", strategy: "{seq}_{seq}"},
+	}
+
+	for _, tt := range testCases {
+		if _, err := storage.NewIDGenerator(tt.strategy); err == nil {
+			t.Fatalf("%s: expected error, but got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// index maps the values of resources to their positions in the resources of a key, so that
+// resources with specific values are found without scanning all of them.
+type index struct {
+	value     func(Resource) (string, bool)
+	positions map[string][]int
+}
+
+// newFieldIndex returns a new index of the resources by the value of a dot separated field path,
+// formatted the same way equality conditions compare them. Resources without the field are not indexed.
+func newFieldIndex(field string, resources []Resource) *index {
+	return newIndex(func(resource Resource) (string, bool) {
+		value, ok := lookup(resource, field)
+		if !ok {
+			return "", false
+		}
+
+		return FormatValue(value), true
+	}, resources)
+}
+
+// newIDIndex returns a new index of the resources by the value of their id field, formatted the
+// same way ids are matched.
+func newIDIndex(idField string, resources []Resource) *index {
+	return newIndex(func(resource Resource) (string, bool) {
+		return FormatID(resource[idField]), true
+	}, resources)
+}
+
+func newIndex(value func(Resource) (string, bool), resources []Resource) *index {
+	i := &index{value: value, positions: make(map[string][]int)}
+	for pos, resource := range resources {
+		if key, ok := i.value(resource); ok {
+			i.positions[key] = append(i.positions[key], pos)
+		}
+	}
+
+	return i
+}
+
+// add the resource in position to the index.
+func (i *index) add(resource Resource, pos int) {
+	key, ok := i.value(resource)
+	if !ok {
+		return
+	}
+
+	// Keep positions in ascending order.
+	positions := i.positions[key]
+	idx := sort.SearchInts(positions, pos)
+	positions = append(positions, 0)
+	copy(positions[idx+1:], positions[idx:])
+	positions[idx] = pos
+
+	i.positions[key] = positions
+}
+
+// remove the resource in position from the index.
+func (i *index) remove(resource Resource, pos int) {
+	key, ok := i.value(resource)
+	if !ok {
+		return
+	}
+
+	positions := i.positions[key]
+	idx := sort.SearchInts(positions, pos)
+	if idx == len(positions) || positions[idx] != pos {
+		return
+	}
+
+	if len(positions) == 1 {
+		delete(i.positions, key)
+		return
+	}
+
+	i.positions[key] = append(positions[:idx], positions[idx+1:]...)
+}
+
+// lookup returns the positions of resources with any of the values, in ascending order.
+func (i *index) lookup(values []string) []int {
+	if len(values) == 1 {
+		return i.positions[values[0]]
+	}
+
+	seen := make(map[int]bool)
+	positions := make([]int, 0)
+	for _, value := range values {
+		for _, pos := range i.positions[value] {
+			if !seen[pos] {
+				seen[pos] = true
+				positions = append(positions, pos)
+			}
+		}
+	}
+
+	sort.Ints(positions)
+
+	return positions
+}
+
+// keyIndexes contains the indexes of the resources of a key.
+type keyIndexes struct {
+	idField string
+	id      *index
+	fields  map[string]*index
+}
+
+// newKeyIndexes returns new indexes of the resources, by their id field and the provided fields.
+func newKeyIndexes(idField string, fields []string, resources []Resource) *keyIndexes {
+	k := &keyIndexes{
+		idField: idField,
+		id:      newIDIndex(idField, resources),
+		fields:  make(map[string]*index, len(fields)),
+	}
+
+	for _, field := range fields {
+		k.fields[field] = newFieldIndex(field, resources)
+	}
+
+	return k
+}
+
+// add the resource in position to all the indexes.
+func (k *keyIndexes) add(resource Resource, pos int) {
+	k.id.add(resource, pos)
+	for _, i := range k.fields {
+		i.add(resource, pos)
+	}
+}
+
+// remove the resource in position from all the indexes.
+func (k *keyIndexes) remove(resource Resource, pos int) {
+	k.id.remove(resource, pos)
+	for _, i := range k.fields {
+		i.remove(resource, pos)
+	}
+}
+
+// candidates returns the positions of resources that may match the filter, in ascending order,
+// using the indexes of the id field and the fields of its equality conditions. Reports false if no
+// index can be used.
+func (k *keyIndexes) candidates(filter Filter) ([]int, bool) {
+	var (
+		positions []int
+		indexed   bool
+	)
+
+	for _, condition := range filter {
+		if condition.Operator != "" && condition.Operator != OperatorEq {
+			continue
+		}
+
+		var conditionPositions []int
+		if i, ok := k.fields[condition.Field]; ok {
+			conditionPositions = i.lookup(condition.Values)
+		} else if condition.Field == k.idField {
+			conditionPositions = k.id.lookup(idValues(condition.Values))
+		} else {
+			continue
+		}
+
+		if !indexed {
+			positions, indexed = conditionPositions, true
+		} else {
+			positions = intersect(positions, conditionPositions)
+		}
+
+		if len(positions) == 0 {
+			break
+		}
+	}
+
+	return positions, indexed
+}
+
+// idValues returns the values of an equality condition as keys of the id index, where composite ids are
+// formatted the same way as ids, e.g. '1,a' instead of '[1,"a"]'.
+func idValues(values []string) []string {
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		var composite []interface{}
+		if strings.HasPrefix(value, "[") && json.Unmarshal([]byte(value), &composite) == nil {
+			value = FormatID(composite)
+		}
+
+		keys = append(keys, value)
+	}
+
+	return keys
+}
+
+// intersect returns the positions contained in both of the ascending positions.
+func intersect(a, b []int) []int {
+	positions := make([]int, 0)
+	for idxA, idxB := 0, 0; idxA < len(a) && idxB < len(b); {
+		switch {
+		case a[idxA] < b[idxB]:
+			idxA++
+		case a[idxA] > b[idxB]:
+			idxB++
+		default:
+			positions = append(positions, a[idxA])
+			idxA++
+			idxB++
+		}
+	}
+
+	return positions
+}
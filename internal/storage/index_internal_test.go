@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyIndexes_Candidates(t *testing.T) {
+	resources := []Resource{
+		{"id": float64(1), "author": "tolkien"},
+		{"id": "2", "author": "orwell"},
+		{"id": []interface{}{float64(3), "a"}, "author": "tolkien"},
+		{"id": float64(4), "title": "anonymous"},
+	}
+
+	k := newKeyIndexes("id", []string{"author"}, resources)
+
+	testCases := []struct {
+		name              string
+		filter            Filter
+		expectedPositions []int
+		expectedIndexed   bool
+	}{
+		{
+			name:              "Id",
+			filter:            Filter{{Field: "id", Values: []string{"2"}}},
+			expectedPositions: []int{1},
+			expectedIndexed:   true,
+		},
+		{
+			name:              "Multiple ids",
+			filter:            Filter{{Field: "id", Operator: OperatorEq, Values: []string{"4", "1", "missing"}}},
+			expectedPositions: []int{0, 3},
+			expectedIndexed:   true,
+		},
+		{
+			name:              "Composite id",
+			filter:            Filter{{Field: "id", Values: []string{`[3,"a"]`}}},
+			expectedPositions: []int{2},
+			expectedIndexed:   true,
+		},
+		{
+			name:              "Indexed field",
+			filter:            Filter{{Field: "author", Values: []string{"tolkien"}}},
+			expectedPositions: []int{0, 2},
+			expectedIndexed:   true,
+		},
+		{
+			name:              "Id and indexed field",
+			filter:            Filter{{Field: "author", Values: []string{"tolkien"}}, {Field: "id", Values: []string{"1", "2"}}},
+			expectedPositions: []int{0},
+			expectedIndexed:   true,
+		},
+		{
+			name:            "Unindexed field",
+			filter:          Filter{{Field: "title", Values: []string{"anonymous"}}},
+			expectedIndexed: false,
+		},
+		{
+			name:            "Id with other operator",
+			filter:          Filter{{Field: "id", Operator: OperatorNe, Values: []string{"1"}}},
+			expectedIndexed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			positions, indexed := k.candidates(tc.filter)
+			if indexed != tc.expectedIndexed {
+				t.Fatalf("expected indexed %v, but got %v", tc.expectedIndexed, indexed)
+			}
+
+			if indexed && !reflect.DeepEqual(positions, tc.expectedPositions) {
+				t.Fatalf("expected positions %v, but got %v", tc.expectedPositions, positions)
+			}
+		})
+	}
+}
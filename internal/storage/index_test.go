@@ -0,0 +1,280 @@
+package storage_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestMemory_Indexes(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	content := `{"books": [
+		{"id": 1, "author": "tolkien", "published": 1954, "meta": {"lang": "en"}},
+		{"id": 2, "author": "orwell", "published": 1949, "meta": {"lang": "en"}},
+		{"id": 3, "author": "tolkien", "published": 1937, "meta": {"lang": "de"}},
+		{"id": 4, "title": "anonymous"}
+	]}`
+	if err = ioutil.WriteFile(f.Name(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := storage.NewMemoryDocument(f.Name(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	indexed, err := storage.NewMemory(doc, "books", storage.WithIndexes("author", "published", "meta.lang"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = indexed.Create(storage.Resource{"author": "tolkien", "published": float64(1977)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = indexed.Update("2", storage.Resource{"author": "tolkien"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = indexed.Replace("3", storage.Resource{"author": "lewis", "meta": map[string]interface{}{"lang": "en"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = indexed.Delete("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = indexed.FindById("1"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	if _, err = indexed.Create(storage.Resource{"id": float64(5), "author": "duplicate"}); !errors.Is(err, storage.ErrResourceAlreadyExists) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceAlreadyExists, err)
+	}
+
+	data, err := indexed.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Indexed queries must return the same resources as unindexed ones.
+	unindexed, err := storage.NewMock(data, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		filter storage.Filter
+	}{
+		{
+			name:   "Single value",
+			filter: storage.Filter{{Field: "author", Values: []string{"tolkien"}}},
+		},
+		{
+			name:   "Multiple values",
+			filter: storage.Filter{{Field: "author", Values: []string{"lewis", "tolkien"}}},
+		},
+		{
+			name:   "Multiple indexed fields",
+			filter: storage.Filter{{Field: "author", Values: []string{"tolkien"}}, {Field: "meta.lang", Values: []string{"en"}}},
+		},
+		{
+			name:   "Indexed and unindexed fields",
+			filter: storage.Filter{{Field: "author", Values: []string{"tolkien"}}, {Field: "published", Operator: storage.OperatorGte, Values: []string{"1950"}}},
+		},
+		{
+			name:   "Number value",
+			filter: storage.Filter{{Field: "published", Values: []string{"1977"}}},
+		},
+		{
+			name:   "Missing value",
+			filter: storage.Filter{{Field: "author", Values: []string{"unknown"}}},
+		},
+		{
+			name:   "Unindexed field",
+			filter: storage.Filter{{Field: "title", Values: []string{"anonymous"}}},
+		},
+		{
+			name:   "Ids",
+			filter: storage.Filter{{Field: "id", Values: []string{"2", "3", "6"}}},
+		},
+		{
+			name:   "Ids and indexed field",
+			filter: storage.Filter{{Field: "id", Values: []string{"2", "3"}}, {Field: "author", Values: []string{"tolkien"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := indexed.Find(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := unindexed.Find(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("expected data %v, but got %v", expected, got)
+			}
+		})
+	}
+
+	// Reloading the file must build the indexes again.
+	if err = ioutil.WriteFile(f.Name(), []byte(`{"books": [{"id": 1, "author": "austen"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = doc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := indexed.Find(storage.Filter{{Field: "author", Values: []string{"austen"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []storage.Resource{{"id": float64(1), "author": "austen"}}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+}
+
+// benchmarkRecords is the number of resources queried by benchmarks.
+const benchmarkRecords = 100000
+
+func newBenchmarkMemory(b *testing.B, opts ...storage.Option) *storage.Memory {
+	b.Helper()
+
+	books := make([]storage.Resource, 0, benchmarkRecords)
+	for idx := 1; idx <= benchmarkRecords; idx++ {
+		books = append(books, storage.Resource{
+			"id":        idx,
+			"title":     "book " + strconv.Itoa(idx),
+			"author":    fmt.Sprintf("author %d", idx%1000),
+			"published": 1900 + idx%120,
+		})
+	}
+
+	contentBytes, err := json.Marshal(map[string]interface{}{"books": books})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { os.Remove(f.Name()) })
+
+	if err = ioutil.WriteFile(f.Name(), contentBytes, 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	doc, err := storage.NewMemoryDocument(f.Name(), time.Hour)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	memory, err := storage.NewMemory(doc, "books", opts...)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return memory
+}
+
+func BenchmarkMemory_Find(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		opts   []storage.Option
+		filter storage.Filter
+	}{
+		{
+			name:   "Unindexed",
+			filter: storage.Filter{{Field: "author", Values: []string{"author 42"}}},
+		},
+		{
+			name:   "Indexed",
+			opts:   []storage.Option{storage.WithIndexes("author", "published")},
+			filter: storage.Filter{{Field: "author", Values: []string{"author 42"}}},
+		},
+		{
+			name:   "Unindexed multiple fields",
+			filter: storage.Filter{{Field: "author", Values: []string{"author 42"}}, {Field: "published", Values: []string{"1942"}}},
+		},
+		{
+			name:   "Indexed multiple fields",
+			opts:   []storage.Option{storage.WithIndexes("author", "published")},
+			filter: storage.Filter{{Field: "author", Values: []string{"author 42"}}, {Field: "published", Values: []string{"1942"}}},
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			memory := newBenchmarkMemory(b, bm.opts...)
+
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if _, err := memory.Find(bm.filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMemory_FindById(b *testing.B) {
+	indexed := newBenchmarkMemory(b)
+
+	data, err := indexed.DB()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// The mock storage scans resources linearly, like the file storage does after parsing the file.
+	unindexed, err := storage.NewMock(data, "books")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmarks := []struct {
+		name       string
+		storageSvc storage.Storage
+	}{
+		{
+			name:       "Unindexed",
+			storageSvc: unindexed,
+		},
+		{
+			name:       "Indexed",
+			storageSvc: indexed,
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				if _, err := bm.storageSvc.FindById(strconv.Itoa(benchmarkRecords - n%1000)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,554 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryDocument holds the contents of a file in memory, shared by the memory storage of every key.
+// Changes are written back to the file asynchronously, at most a flush interval after they are made.
+// Stored resources are never modified in place, so they can be safely returned to callers.
+type MemoryDocument struct {
+	filename      string
+	flushInterval time.Duration
+	// fileKey is the key of the only resources of the file, if it doesn't contain the ones of every key.
+	fileKey string
+
+	mu       sync.RWMutex
+	data     Database
+	singular map[string]Resource
+	dirty    bool
+	timer    *time.Timer
+	// sum is the checksum of the file contents, as last loaded or written.
+	sum [sha256.Size]byte
+	// indexes contains the indexes of resources per key, maintained on every change.
+	indexes      map[string]*keyIndexes
+	indexFields  map[string][]string
+	indexIDField map[string]string
+
+	// flushMu serializes writes to the file.
+	flushMu sync.Mutex
+}
+
+// NewMemoryDocument loads the contents of the file in memory, and returns a new memory document instance,
+// for a file which contains the resources of every key.
+func NewMemoryDocument(filename string, flushInterval time.Duration) (*MemoryDocument, error) {
+	return newMemoryDocument(filename, "", flushInterval)
+}
+
+// NewResourceMemoryDocument loads the contents of the file in memory, and returns a new memory document
+// instance, for a file which contains only the resources of the key, e.g. 'books.json' with the array of 'books'.
+func NewResourceMemoryDocument(filename, key string, flushInterval time.Duration) (*MemoryDocument, error) {
+	return newMemoryDocument(filename, key, flushInterval)
+}
+
+// NewContentMemoryDocument returns a new memory document instance, that holds the json compatible contents
+// in memory only, e.g. generated resources. Changes are never written to a file.
+func NewContentMemoryDocument(content map[string]interface{}) (*MemoryDocument, error) {
+	data, singular, err := contentResources(content, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryDocument{
+		data:         data,
+		singular:     singular,
+		indexes:      make(map[string]*keyIndexes),
+		indexFields:  make(map[string][]string),
+		indexIDField: make(map[string]string),
+	}, nil
+}
+
+func newMemoryDocument(filename, fileKey string, flushInterval time.Duration) (*MemoryDocument, error) {
+	contentBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data, singular, err := decodeContent(formatOf(filename), contentBytes, fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryDocument{
+		filename:      filename,
+		flushInterval: flushInterval,
+		fileKey:       fileKey,
+		data:          data,
+		singular:      singular,
+		sum:           sha256.Sum256(contentBytes),
+		indexes:       make(map[string]*keyIndexes),
+		indexFields:   make(map[string][]string),
+		indexIDField:  make(map[string]string),
+	}, nil
+}
+
+// Reload the contents of the file in memory, if it was changed by anyone other than the document
+// itself. Changes of the file take precedence over any pending changes. On failure, the current
+// contents are kept.
+func (d *MemoryDocument) Reload() error {
+	if d.filename == "" {
+		return nil
+	}
+
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	contentBytes, err := ioutil.ReadFile(d.filename)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(contentBytes)
+
+	d.mu.RLock()
+	unchanged := sum == d.sum
+	d.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	data, singular, err := decodeContent(formatOf(d.filename), contentBytes, d.fileKey)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.data = data
+	d.singular = singular
+	d.sum = sum
+	d.dirty = false
+
+	for key := range d.indexIDField {
+		d.buildIndexes(key)
+	}
+
+	return nil
+}
+
+// Flush writes any pending changes to the file.
+func (d *MemoryDocument) Flush() error {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	d.mu.Lock()
+	if !d.dirty {
+		d.mu.Unlock()
+		return nil
+	}
+
+	// Stored resources are immutable, so a shallow copy is a consistent snapshot.
+	data := make(Database, len(d.data))
+	for key, resources := range d.data {
+		data[key] = resources
+	}
+
+	singular := make(map[string]Resource, len(d.singular))
+	for key, resource := range d.singular {
+		singular[key] = resource
+	}
+
+	idFields := make(map[string]string, len(d.indexIDField))
+	for key, idField := range d.indexIDField {
+		idFields[key] = idField
+	}
+
+	d.dirty = false
+	d.mu.Unlock()
+
+	contentBytes, err := encodeFile(d.filename, d.fileKey, data, singular, idFields)
+	if err == nil {
+		err = writeFile(d.filename, contentBytes)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil {
+		// Keep the changes pending, to retry on the next flush.
+		d.dirty = true
+		return err
+	}
+
+	d.sum = sha256.Sum256(contentBytes)
+
+	return nil
+}
+
+// Close stops any scheduled flush, and writes any pending changes to the file.
+func (d *MemoryDocument) Close() error {
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.mu.Unlock()
+
+	return d.Flush()
+}
+
+// markDirty records a change, and schedules a flush if there isn't one already. Must be called
+// while holding the write lock.
+func (d *MemoryDocument) markDirty() {
+	// Contents without a file are never written.
+	if d.filename == "" {
+		return
+	}
+
+	d.dirty = true
+
+	if d.timer != nil {
+		return
+	}
+
+	d.timer = time.AfterFunc(d.flushInterval, func() {
+		d.mu.Lock()
+		d.timer = nil
+		d.mu.Unlock()
+
+		// Retry on the next interval, if flush fails.
+		if err := d.Flush(); err != nil {
+			d.mu.Lock()
+			d.markDirty()
+			d.mu.Unlock()
+		}
+	})
+}
+
+// Keys returns the keys of resources and singular resources of the document, in alphabetical order.
+func (d *MemoryDocument) Keys() ([]string, []string) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	resourceKeys := make([]string, 0, len(d.data))
+	for key := range d.data {
+		resourceKeys = append(resourceKeys, key)
+	}
+
+	singularKeys := make([]string, 0, len(d.singular))
+	for key := range d.singular {
+		singularKeys = append(singularKeys, key)
+	}
+
+	sort.Strings(resourceKeys)
+	sort.Strings(singularKeys)
+
+	return resourceKeys, singularKeys
+}
+
+// setIndexes sets the id field and the fields, that the resources of key are indexed by.
+func (d *MemoryDocument) setIndexes(key, idField string, fields []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.indexIDField[key] = idField
+	d.indexFields[key] = fields
+	d.buildIndexes(key)
+}
+
+// buildIndexes of the resources of key from scratch. Must be called while holding the write lock.
+func (d *MemoryDocument) buildIndexes(key string) {
+	resources, ok := d.data[key]
+	if !ok {
+		delete(d.indexes, key)
+		return
+	}
+
+	d.indexes[key] = newKeyIndexes(d.indexIDField[key], d.indexFields[key], resources)
+}
+
+// Memory implements the storage interface, and uses a memory document as 'database'.
+type Memory struct {
+	doc *MemoryDocument
+	key string
+	options
+}
+
+// NewMemory returns a new memory instance. Resources of the key are indexed by their id field,
+// along with any fields set with WithIndexes.
+func NewMemory(doc *MemoryDocument, key string, opts ...Option) (*Memory, error) {
+	m := &Memory{doc: doc, key: key, options: newOptions(opts)}
+
+	// The storage for all resources isn't bound to a key.
+	if key != "" {
+		doc.setIndexes(key, m.idField, m.indexes)
+	}
+
+	return m, nil
+}
+
+// IDField returns the name of the id field of memory resources.
+func (m *Memory) IDField() string {
+	return m.idField
+}
+
+// Find all memory resources for the specific key, that match the provided filter.
+func (m *Memory) Find(filter Filter) ([]Resource, error) {
+	m.doc.mu.RLock()
+	defer m.doc.mu.RUnlock()
+
+	if err := checkResourceKeyExists(m.doc.data, m.key); err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	resources := m.doc.data[m.key]
+
+	if indexes, ok := m.doc.indexes[m.key]; ok {
+		if positions, ok := indexes.candidates(filter); ok {
+			filtered := make([]Resource, 0, len(positions))
+			for _, pos := range positions {
+				if filter.Match(resources[pos]) {
+					filtered = append(filtered, resources[pos])
+				}
+			}
+
+			return filtered, nil
+		}
+	}
+
+	return filterResources(resources, filter), nil
+}
+
+// FindById a memory resource for the specific key.
+func (m *Memory) FindById(id string) (Resource, error) {
+	m.doc.mu.RLock()
+	defer m.doc.mu.RUnlock()
+
+	return m.findById(id)
+}
+
+// Create a new memory resource for the specific key.
+func (m *Memory) Create(newResource Resource) (Resource, error) {
+	m.doc.mu.Lock()
+	defer m.doc.mu.Unlock()
+
+	if err := checkResourceKeyExists(m.doc.data, m.key); err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	_, ok := newResource[m.idField]
+	if !ok {
+		id, err := m.idGenerator.Generate(resourceIDs(m.doc.data[m.key], m.idField))
+		if err != nil {
+			return nil, err
+		}
+
+		newResource[m.idField] = id
+	} else if _, err := m.findById(FormatID(newResource[m.idField])); err == nil {
+		return nil, ErrResourceAlreadyExists
+	}
+
+	newResources := make([]Resource, 0, len(m.doc.data[m.key])+1)
+	newResources = append(newResources, m.doc.data[m.key]...)
+	m.doc.data[m.key] = append(newResources, newResource)
+	m.doc.markDirty()
+
+	if indexes, ok := m.doc.indexes[m.key]; ok {
+		indexes.add(newResource, len(newResources))
+	}
+
+	return newResource, nil
+}
+
+// Replace an existing memory resource for the specific key.
+func (m *Memory) Replace(id string, replaced Resource) (Resource, error) {
+	m.doc.mu.Lock()
+	defer m.doc.mu.Unlock()
+
+	// Check if resource with the requested id exists.
+	current, err := m.findById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the id, along with its original type.
+	replaced[m.idField] = current[m.idField]
+
+	m.set(id, replaced)
+
+	return replaced, nil
+}
+
+// Update an existing memory resource for the specific key.
+func (m *Memory) Update(id string, updatedReq Resource) (Resource, error) {
+	m.doc.mu.Lock()
+	defer m.doc.mu.Unlock()
+
+	// Check if resource with the requested id exists and retrieve it.
+	current, err := m.findById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply any changes to a copy of the current resource.
+	updated := make(Resource, len(current)+len(updatedReq))
+	for key, val := range current {
+		updated[key] = val
+	}
+
+	for key, val := range updatedReq {
+		updated[key] = val
+	}
+
+	// Keep the id, along with its original type.
+	updated[m.idField] = current[m.idField]
+
+	m.set(id, updated)
+
+	return updated, nil
+}
+
+// Delete an existing memory resource for the specific key.
+func (m *Memory) Delete(id string) error {
+	m.doc.mu.Lock()
+	defer m.doc.mu.Unlock()
+
+	// Check if resource with the requested id exists.
+	if _, err := m.findById(id); err != nil {
+		return err
+	}
+
+	newResources := make([]Resource, 0)
+	for _, d := range m.doc.data[m.key] {
+		if MatchID(d[m.idField], id) {
+			continue
+		}
+
+		newResources = append(newResources, d)
+	}
+
+	m.doc.data[m.key] = newResources
+	m.doc.markDirty()
+
+	// Positions of the following resources changed, so indexes are built again.
+	if _, ok := m.doc.indexes[m.key]; ok {
+		m.doc.buildIndexes(m.key)
+	}
+
+	return nil
+}
+
+// DB returns all the memory resources.
+func (m *Memory) DB() (Database, error) {
+	m.doc.mu.RLock()
+	defer m.doc.mu.RUnlock()
+
+	data := make(Database, len(m.doc.data))
+	for key, resources := range m.doc.data {
+		data[key] = resources
+	}
+
+	return data, nil
+}
+
+// findById a memory resource for the specific key. Must be called while holding the lock.
+func (m *Memory) findById(id string) (Resource, error) {
+	if err := checkResourceKeyExists(m.doc.data, m.key); err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	if indexes, ok := m.doc.indexes[m.key]; ok && indexes.idField == m.idField {
+		positions := indexes.id.lookup([]string{id})
+		if len(positions) == 0 {
+			return nil, ErrResourceNotFound
+		}
+
+		return m.doc.data[m.key][positions[0]], nil
+	}
+
+	return findById(m.doc.data[m.key], m.idField, id)
+}
+
+// set the resource with the requested id, in a new copy of the resources. Must be called while
+// holding the write lock.
+func (m *Memory) set(id string, resource Resource) {
+	indexes, indexed := m.doc.indexes[m.key]
+
+	newResources := make([]Resource, 0, len(m.doc.data[m.key]))
+	for pos, d := range m.doc.data[m.key] {
+		if MatchID(d[m.idField], id) {
+			newResources = append(newResources, resource)
+
+			if indexed {
+				indexes.remove(d, pos)
+				indexes.add(resource, pos)
+			}
+		} else {
+			newResources = append(newResources, d)
+		}
+	}
+
+	m.doc.data[m.key] = newResources
+	m.doc.markDirty()
+}
+
+// SingularMemory implements the singular storage interface, and uses a memory document as 'database'.
+type SingularMemory struct {
+	doc *MemoryDocument
+	key string
+}
+
+// NewSingularMemory returns a new singular memory instance.
+func NewSingularMemory(doc *MemoryDocument, key string) (*SingularMemory, error) {
+	return &SingularMemory{doc: doc, key: key}, nil
+}
+
+// Get the singular memory resource for the specific key.
+func (m *SingularMemory) Get() (Resource, error) {
+	m.doc.mu.RLock()
+	defer m.doc.mu.RUnlock()
+
+	resource, ok := m.doc.singular[m.key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	return resource, nil
+}
+
+// Replace the singular memory resource for the specific key.
+func (m *SingularMemory) Replace(replaced Resource) (Resource, error) {
+	m.doc.mu.Lock()
+	defer m.doc.mu.Unlock()
+
+	if _, ok := m.doc.singular[m.key]; !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	m.doc.singular[m.key] = replaced
+	m.doc.markDirty()
+
+	return replaced, nil
+}
+
+// Update the singular memory resource for the specific key.
+func (m *SingularMemory) Update(updatedReq Resource) (Resource, error) {
+	m.doc.mu.Lock()
+	defer m.doc.mu.Unlock()
+
+	current, ok := m.doc.singular[m.key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	// Apply any changes to a copy of the current resource.
+	updated := make(Resource, len(current)+len(updatedReq))
+	for key, val := range current {
+		updated[key] = val
+	}
+
+	for key, val := range updatedReq {
+		updated[key] = val
+	}
+
+	m.doc.singular[m.key] = updated
+	m.doc.markDirty()
+
+	return updated, nil
+}
@@ -0,0 +1,337 @@
+package storage_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestMemory(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	content := `{"posts": [{"id": "1", "title": "json-server"}], "profile": {"name": "typicode"}}`
+	if err = ioutil.WriteFile(f.Name(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := storage.NewMemoryDocument(f.Name(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	posts, err := storage.NewMemory(doc, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := storage.NewSingularMemory(doc, "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := posts.FindById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "2", "title": "json-server in go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "2", "title": "duplicate"}); !errors.Is(err, storage.ErrResourceAlreadyExists) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceAlreadyExists, err)
+	}
+
+	if _, err = posts.Update("1", storage.Resource{"title": "json-server in js"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = profile.Update(storage.Resource{"age": float64(30)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Previously returned resources must not be modified.
+	if expected := (storage.Resource{"id": "1", "title": "json-server"}); !reflect.DeepEqual(found, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, found)
+	}
+
+	// Changes must be served from memory, before they are flushed to the file.
+	got, err := posts.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []storage.Resource{
+		{"id": "1", "title": "json-server in js"},
+		{"id": "2", "title": "json-server in go"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+
+	contentBytes, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contentBytes) != content {
+		t.Fatalf("expected file content %v, but got %v", content, string(contentBytes))
+	}
+
+	// Closing the document must flush the pending changes.
+	if err = doc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	contentBytes, err = ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var flushed map[string]interface{}
+	if err = json.Unmarshal(contentBytes, &flushed); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedContent := map[string]interface{}{
+		"posts": []interface{}{
+			map[string]interface{}{"id": "1", "title": "json-server in js"},
+			map[string]interface{}{"id": "2", "title": "json-server in go"},
+		},
+		"profile": map[string]interface{}{"name": "typicode", "age": float64(30)},
+	}
+	if !reflect.DeepEqual(flushed, expectedContent) {
+		t.Fatalf("expected file content %v, but got %v", expectedContent, flushed)
+	}
+}
+
+func TestMemoryDocument_FlushInterval(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err = ioutil.WriteFile(f.Name(), []byte(`{"posts": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := storage.NewMemoryDocument(f.Name(), 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	posts, err := storage.NewMemory(doc, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "1", "title": "json-server"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the scheduled flush.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		fileStorage, err := storage.NewFile(f.Name(), "posts")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = fileStorage.FindById("1"); err == nil {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("expected changes to be flushed to file")
+}
+
+func TestMemoryDocument_Reload(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err = ioutil.WriteFile(f.Name(), []byte(`{"posts": [{"id": "1"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := storage.NewMemoryDocument(f.Name(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	posts, err := storage.NewMemory(doc, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reloading own changes must keep the current contents.
+	if err = doc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = doc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.FindById("3"); err != nil {
+		t.Fatalf("expected pending resource to be kept, but got %v", err)
+	}
+
+	// Reloading invalid contents must fail and keep the current contents.
+	if err = ioutil.WriteFile(f.Name(), []byte(`{"posts": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = doc.Reload(); err == nil {
+		t.Fatal("expected error on invalid contents, but got nil")
+	}
+
+	if _, err = posts.FindById("3"); err != nil {
+		t.Fatalf("expected current resource to be kept, but got %v", err)
+	}
+
+	// Reloading external changes must replace the current contents.
+	if err = ioutil.WriteFile(f.Name(), []byte(`{"posts": [{"id": "4"}], "comments": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = doc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := posts.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []storage.Resource{{"id": "4"}}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+
+	comments, err := storage.NewMemory(doc, "comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = comments.Find(nil); err != nil {
+		t.Fatalf("expected new resource key to be available, but got %v", err)
+	}
+}
+
+func TestResourceMemoryDocument(t *testing.T) {
+	f, err := ioutil.TempFile(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err = ioutil.WriteFile(f.Name(), []byte(`[{"id": "1"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := storage.NewResourceMemoryDocument(f.Name(), "books", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	books, err := storage.NewMemory(doc, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = books.Create(storage.Resource{"id": "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = doc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	contentBytes, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got interface{}
+	if err = json.Unmarshal(contentBytes, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected file content %v, but got %v", expected, got)
+	}
+}
+
+func TestContentMemoryDocument(t *testing.T) {
+	content := map[string]interface{}{
+		"posts":   []interface{}{map[string]interface{}{"id": float64(1), "title": "generated"}},
+		"profile": map[string]interface{}{"name": "typicode"},
+	}
+
+	doc, err := storage.NewContentMemoryDocument(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceKeys, singularKeys := doc.Keys()
+	if expected := []string{"posts"}; !reflect.DeepEqual(resourceKeys, expected) {
+		t.Fatalf("expected resource keys %v, but got %v", expected, resourceKeys)
+	}
+
+	if expected := []string{"profile"}; !reflect.DeepEqual(singularKeys, expected) {
+		t.Fatalf("expected singular keys %v, but got %v", expected, singularKeys)
+	}
+
+	storageSvc, err := storage.NewMemory(doc, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := storageSvc.Create(storage.Resource{"title": "created"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"id": float64(2), "title": "created"}); !reflect.DeepEqual(created, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, created)
+	}
+
+	// Contents without a file are never written.
+	if err = doc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = doc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = storageSvc.FindById("2"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,66 @@
+package storage
+
+// Merged implements the storage interface for all the resources of multiple storage, e.g. of multiple
+// files. Resources aren't bound to a key, so only DB is supported, while other operations report
+// resources as not found.
+type Merged struct {
+	storages []Storage
+	options
+}
+
+// NewMerged returns a new merged instance, of the provided storage.
+func NewMerged(storages ...Storage) (*Merged, error) {
+	return &Merged{storages: storages, options: newOptions(nil)}, nil
+}
+
+// IDField returns the name of the id field of resources.
+func (m *Merged) IDField() string {
+	return m.idField
+}
+
+// Find reports resources as not found, as they aren't bound to a key.
+func (m *Merged) Find(Filter) ([]Resource, error) {
+	return nil, ErrResourceNotFound
+}
+
+// FindById reports resources as not found, as they aren't bound to a key.
+func (m *Merged) FindById(string) (Resource, error) {
+	return nil, ErrResourceNotFound
+}
+
+// Create reports resources as not found, as they aren't bound to a key.
+func (m *Merged) Create(Resource) (Resource, error) {
+	return nil, ErrResourceNotFound
+}
+
+// Replace reports resources as not found, as they aren't bound to a key.
+func (m *Merged) Replace(string, Resource) (Resource, error) {
+	return nil, ErrResourceNotFound
+}
+
+// Update reports resources as not found, as they aren't bound to a key.
+func (m *Merged) Update(string, Resource) (Resource, error) {
+	return nil, ErrResourceNotFound
+}
+
+// Delete reports resources as not found, as they aren't bound to a key.
+func (m *Merged) Delete(string) error {
+	return ErrResourceNotFound
+}
+
+// DB returns all the resources of every storage.
+func (m *Merged) DB() (Database, error) {
+	data := make(Database)
+	for _, storageSvc := range m.storages {
+		storageData, err := storageSvc.DB()
+		if err != nil {
+			return nil, err
+		}
+
+		for key, resources := range storageData {
+			data[key] = resources
+		}
+	}
+
+	return data, nil
+}
@@ -3,16 +3,22 @@ package storage
 type Mock struct {
 	data Database
 	key  string
+	options
 }
 
 // NewMock returns a new mock instance.
-func NewMock(data Database, key string) (*Mock, error) {
-	return &Mock{data: data, key: key}, nil
+func NewMock(data Database, key string, opts ...Option) (*Mock, error) {
+	return &Mock{data: data, key: key, options: newOptions(opts)}, nil
 }
 
-// Find all mock resources for the specific key.
-func (m *Mock) Find() ([]Resource, error) {
-	return m.data[m.key], nil
+// IDField returns the name of the id field of mock resources.
+func (m *Mock) IDField() string {
+	return m.idField
+}
+
+// Find all mock resources for the specific key, that match the provided filter.
+func (m *Mock) Find(filter Filter) ([]Resource, error) {
+	return filterResources(m.data[m.key], filter), nil
 }
 
 // FindById a mock resource for the specific key.
@@ -22,7 +28,7 @@ func (m *Mock) FindById(id string) (Resource, error) {
 	}
 
 	for _, resource := range m.data[m.key] {
-		if resource["id"] == id {
+		if MatchID(resource[m.idField], id) {
 			return resource, nil
 		}
 	}
@@ -36,12 +42,17 @@ func (m *Mock) Create(newResource Resource) (Resource, error) {
 		return nil, ErrResourceNotFound
 	}
 
-	_, ok := newResource["id"]
+	_, ok := newResource[m.idField]
 	if !ok {
-		newResource["id"] = generateNewId(m.data[m.key])
+		id, err := m.idGenerator.Generate(resourceIDs(m.data[m.key], m.idField))
+		if err != nil {
+			return nil, err
+		}
+
+		newResource[m.idField] = id
 	} else {
 		for _, resource := range m.data[m.key] {
-			if resource["id"] == newResource["id"] {
+			if FormatID(resource[m.idField]) == FormatID(newResource[m.idField]) {
 				return nil, ErrResourceAlreadyExists
 			}
 		}
@@ -60,15 +71,17 @@ func (m *Mock) Replace(id string, replaced Resource) (Resource, error) {
 	}
 
 	// Check if resource with the requested id exists.
-	if _, err := m.FindById(id); err != nil {
+	current, err := m.FindById(id)
+	if err != nil {
 		return nil, err
 	}
 
-	replaced["id"] = id
+	// Keep the id, along with its original type.
+	replaced[m.idField] = current[m.idField]
 
 	newResources := make([]Resource, 0)
 	for _, d := range m.data[m.key] {
-		if d["id"] == id {
+		if MatchID(d[m.idField], id) {
 			newResources = append(newResources, replaced)
 		} else {
 			newResources = append(newResources, d)
@@ -92,16 +105,19 @@ func (m *Mock) Update(id string, updatedReq Resource) (Resource, error) {
 		return nil, err
 	}
 
+	currentID := updated[m.idField]
+
 	// Apply any changes to current resource.
 	for key, val := range updatedReq {
 		updated[key] = val
 	}
 
-	updated["id"] = id
+	// Keep the id, along with its original type.
+	updated[m.idField] = currentID
 
 	newResources := make([]Resource, 0)
 	for _, d := range m.data[m.key] {
-		if d["id"] == id {
+		if MatchID(d[m.idField], id) {
 			newResources = append(newResources, updated)
 		} else {
 			newResources = append(newResources, d)
@@ -126,7 +142,7 @@ func (m *Mock) Delete(id string) error {
 
 	newResources := make([]Resource, 0)
 	for _, d := range m.data[m.key] {
-		if d["id"] == id {
+		if MatchID(d[m.idField], id) {
 			continue
 		}
 
@@ -146,3 +162,50 @@ func (m *Mock) DB() (Database, error) {
 func (m *Mock) SetData(data Database) {
 	m.data = data
 }
+
+// SingularMock implements the singular storage interface, and uses a map as 'database'.
+type SingularMock struct {
+	data map[string]Resource
+	key  string
+}
+
+// NewSingularMock returns a new singular mock instance.
+func NewSingularMock(data map[string]Resource, key string) (*SingularMock, error) {
+	return &SingularMock{data: data, key: key}, nil
+}
+
+// Get the singular mock resource for the specific key.
+func (m *SingularMock) Get() (Resource, error) {
+	resource, ok := m.data[m.key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	return resource, nil
+}
+
+// Replace the singular mock resource for the specific key.
+func (m *SingularMock) Replace(replaced Resource) (Resource, error) {
+	if _, ok := m.data[m.key]; !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	m.data[m.key] = replaced
+
+	return replaced, nil
+}
+
+// Update the singular mock resource for the specific key.
+func (m *SingularMock) Update(updatedReq Resource) (Resource, error) {
+	updated, ok := m.data[m.key]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	// Apply any changes to current resource.
+	for key, val := range updatedReq {
+		updated[key] = val
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+// Option configures optional settings of a storage instance.
+type Option func(*options)
+
+type options struct {
+	idField     string
+	idGenerator IDGenerator
+	indexes     []string
+}
+
+// WithIDField sets the name of the id field of resources. Defaults to 'id'.
+func WithIDField(idField string) Option {
+	return func(o *options) {
+		o.idField = idField
+	}
+}
+
+// WithIDGenerator sets the generator of ids for new resources. Defaults to auto increment ids.
+func WithIDGenerator(idGenerator IDGenerator) Option {
+	return func(o *options) {
+		o.idGenerator = idGenerator
+	}
+}
+
+// WithIndexes sets the dot separated paths of fields, that resources are indexed by. Equality
+// conditions on indexed fields find resources without scanning all of them, for storage that
+// supports indexes.
+func WithIndexes(fields ...string) Option {
+	return func(o *options) {
+		o.indexes = fields
+	}
+}
+
+func newOptions(opts []Option) options {
+	// Auto increment generator is always valid.
+	idGenerator, _ := NewIDGenerator(IDGeneratorAutoIncrement)
+
+	o := options{idField: "id", idGenerator: idGenerator}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
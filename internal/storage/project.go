@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"strings"
+)
+
+// fieldTree represents a set of dot separated field paths as a tree, where an empty
+// subtree selects the whole value of a field.
+type fieldTree map[string]fieldTree
+
+// Project returns a copy of resource that contains only the provided fields. Nested fields can
+// be selected with a dot separated path, e.g. 'author.name', which is applied to every element
+// of arrays along the path. Missing fields are omitted.
+func Project(resource Resource, fields []string) Resource {
+	if len(fields) == 0 {
+		return resource
+	}
+
+	projected, _ := projectValue(map[string]interface{}(resource), newFieldTree(fields)).(map[string]interface{})
+
+	return projected
+}
+
+// ProjectAll returns a copy of resources, where each resource contains only the provided fields.
+func ProjectAll(resources []Resource, fields []string) []Resource {
+	if len(fields) == 0 {
+		return resources
+	}
+
+	projected := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		projected = append(projected, Project(resource, fields))
+	}
+
+	return projected
+}
+
+func newFieldTree(fields []string) fieldTree {
+	tree := make(fieldTree)
+
+	for _, field := range fields {
+		node := tree
+		parts := strings.Split(field, ".")
+
+		for idx, part := range parts {
+			child, ok := node[part]
+			// An already selected whole value can't be narrowed down.
+			if ok && len(child) == 0 {
+				break
+			}
+
+			if !ok {
+				child = make(fieldTree)
+				node[part] = child
+			}
+
+			// Selecting the whole value overrides any nested selection.
+			if idx == len(parts)-1 {
+				node[part] = make(fieldTree)
+			}
+
+			node = child
+		}
+	}
+
+	return tree
+}
+
+// projectValue returns the parts of value selected by tree. Reports nil if nothing is selected.
+func projectValue(value interface{}, tree fieldTree) interface{} {
+	if len(tree) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case Resource:
+		return projectValue(map[string]interface{}(v), tree)
+	case map[string]interface{}:
+		projected := make(map[string]interface{})
+		for field, subtree := range tree {
+			val, ok := v[field]
+			if !ok {
+				continue
+			}
+
+			if val = projectValue(val, subtree); val != nil || len(subtree) == 0 {
+				projected[field] = val
+			}
+		}
+
+		return projected
+	case []interface{}:
+		projected := make([]interface{}, 0, len(v))
+		for _, val := range v {
+			if val = projectValue(val, tree); val != nil {
+				projected = append(projected, val)
+			}
+		}
+
+		return projected
+	case []Resource:
+		values := make([]interface{}, 0, len(v))
+		for _, val := range v {
+			values = append(values, val)
+		}
+
+		return projectValue(values, tree)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package storage_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestProject(t *testing.T) {
+	resource := storage.Resource{
+		"id":    "1",
+		"title": "Clean Code",
+		"author": map[string]interface{}{
+			"name": "Robert Martin",
+			"born": float64(1952),
+		},
+		"reviews": []interface{}{
+			map[string]interface{}{"user": "john", "rating": float64(5)},
+			map[string]interface{}{"user": "jane", "rating": float64(4)},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		fields   []string
+		expected storage.Resource
+	}{
+		{
+			name:     "Project without fields",
+			fields:   nil,
+			expected: resource,
+		},
+		{
+			name:     "Project top level fields",
+			fields:   []string{"id", "title"},
+			expected: storage.Resource{"id": "1", "title": "Clean Code"},
+		},
+		{
+			name:   "Project nested field",
+			fields: []string{"title", "author.name"},
+			expected: storage.Resource{
+				"title":  "Clean Code",
+				"author": map[string]interface{}{"name": "Robert Martin"},
+			},
+		},
+		{
+			name:   "Project nested field of array elements",
+			fields: []string{"reviews.rating"},
+			expected: storage.Resource{
+				"reviews": []interface{}{
+					map[string]interface{}{"rating": float64(5)},
+					map[string]interface{}{"rating": float64(4)},
+				},
+			},
+		},
+		{
+			name:   "Project whole and nested field",
+			fields: []string{"author.name", "author"},
+			expected: storage.Resource{
+				"author": resource["author"],
+			},
+		},
+		{
+			name:     "Project missing fields",
+			fields:   []string{"id", "subtitle", "title.length"},
+			expected: storage.Resource{"id": "1"},
+		},
+	}
+
+	for _, tt := range testCases {
+		if got := storage.Project(resource, tt.fields); !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("%s: expected resource %v, but got %v", tt.name, tt.expected, got)
+		}
+	}
+}
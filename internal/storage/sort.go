@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"sort"
+)
+
+// SortField represents a field that resources are sorted by.
+type SortField struct {
+	// Field is the dot separated path of the field, e.g. 'author.name'.
+	Field string
+	// Descending reverses the natural order of the field values.
+	Descending bool
+}
+
+// Sort returns a copy of resources stably sorted by the provided fields, in order of priority.
+// Values of different types are ordered by type, while resources missing a field are always
+// placed after the ones that have it, regardless of the sort direction.
+func Sort(resources []Resource, fields []SortField) []Resource {
+	sorted := make([]Resource, len(resources))
+	copy(sorted, resources)
+
+	if len(fields) == 0 {
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareResources(sorted[i], sorted[j], fields) < 0
+	})
+
+	return sorted
+}
+
+// SortKey represents the value of a sort field in a resource.
+type SortKey struct {
+	// Value of the field.
+	Value interface{} `json:"v,omitempty"`
+	// Missing reports whether the resource does not have the field.
+	Missing bool `json:"m,omitempty"`
+}
+
+// SortKeys returns the values of the sort fields in resource, which define its
+// position in a collection sorted by these fields.
+func SortKeys(resource Resource, fields []SortField) []SortKey {
+	keys := make([]SortKey, 0, len(fields))
+	for _, field := range fields {
+		value, ok := lookup(resource, field.Field)
+		keys = append(keys, SortKey{Value: value, Missing: !ok})
+	}
+
+	return keys
+}
+
+// SortAfter returns the resources of a collection, already sorted by the provided fields,
+// that are positioned after the provided sort keys.
+func SortAfter(sorted []Resource, fields []SortField, keys []SortKey) []Resource {
+	idx := sort.Search(len(sorted), func(i int) bool {
+		return compareSortKeys(SortKeys(sorted[i], fields), keys, fields) > 0
+	})
+
+	return sorted[idx:]
+}
+
+// compareResources compares two resources by the provided fields and returns -1, 0 or +1.
+func compareResources(a, b Resource, fields []SortField) int {
+	return compareSortKeys(SortKeys(a, fields), SortKeys(b, fields), fields)
+}
+
+// compareSortKeys compares two sets of sort keys by the provided fields and returns -1, 0 or +1.
+func compareSortKeys(a, b []SortKey, fields []SortField) int {
+	for idx, field := range fields {
+		if idx >= len(a) || idx >= len(b) {
+			break
+		}
+
+		switch {
+		case a[idx].Missing && b[idx].Missing:
+			continue
+		case a[idx].Missing:
+			return 1
+		case b[idx].Missing:
+			return -1
+		}
+
+		cmp := compareValues(a[idx].Value, b[idx].Value)
+		if cmp == 0 {
+			continue
+		}
+
+		if field.Descending {
+			return -cmp
+		}
+
+		return cmp
+	}
+
+	return 0
+}
@@ -0,0 +1,120 @@
+package storage_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestSort(t *testing.T) {
+	resources := []storage.Resource{
+		{"id": "1", "title": "Clean Code", "published": float64(2008)},
+		{"id": "2", "title": "Crime and punishment", "published": float64(1866)},
+		{"id": "3", "title": "Refactoring", "published": float64(2008)},
+		{"id": "4", "title": "Untitled"},
+		{"id": "5", "title": "The Pragmatic Programmer", "published": "1999"},
+		{"id": "6", "title": "Unknown", "published": nil},
+	}
+
+	testCases := []struct {
+		name        string
+		fields      []storage.SortField
+		expectedIds []string
+	}{
+		{
+			name:        "Sort without fields",
+			fields:      nil,
+			expectedIds: []string{"1", "2", "3", "4", "5", "6"},
+		},
+		{
+			name:        "Sort ascending by string field",
+			fields:      []storage.SortField{{Field: "title"}},
+			expectedIds: []string{"1", "2", "3", "5", "6", "4"},
+		},
+		{
+			name:        "Sort ascending by mixed types field",
+			fields:      []storage.SortField{{Field: "published"}},
+			expectedIds: []string{"6", "2", "1", "3", "5", "4"},
+		},
+		{
+			name:        "Sort descending by mixed types field",
+			fields:      []storage.SortField{{Field: "published", Descending: true}},
+			expectedIds: []string{"5", "1", "3", "2", "6", "4"},
+		},
+		{
+			name: "Sort by multiple fields",
+			fields: []storage.SortField{
+				{Field: "published", Descending: true},
+				{Field: "title", Descending: true},
+			},
+			expectedIds: []string{"5", "3", "1", "2", "6", "4"},
+		},
+	}
+
+	for _, tt := range testCases {
+		original := make([]storage.Resource, len(resources))
+		copy(original, resources)
+
+		got := storage.Sort(resources, tt.fields)
+
+		gotIds := make([]string, 0, len(got))
+		for _, resource := range got {
+			gotIds = append(gotIds, resource["id"].(string))
+		}
+
+		if !reflect.DeepEqual(gotIds, tt.expectedIds) {
+			t.Fatalf("%s: expected order %v, but got %v", tt.name, tt.expectedIds, gotIds)
+		}
+
+		if !reflect.DeepEqual(resources, original) {
+			t.Fatalf("%s: expected resources to remain unchanged", tt.name)
+		}
+	}
+}
+
+func TestSortAfter(t *testing.T) {
+	fields := []storage.SortField{{Field: "published", Descending: true}, {Field: "id"}}
+
+	sorted := storage.Sort([]storage.Resource{
+		{"id": "1", "published": float64(2008)},
+		{"id": "2", "published": float64(1866)},
+		{"id": "3", "published": float64(2008)},
+		{"id": "4"},
+	}, fields)
+
+	testCases := []struct {
+		name        string
+		keys        []storage.SortKey
+		expectedIds []string
+	}{
+		{
+			name:        "After first resource",
+			keys:        storage.SortKeys(sorted[0], fields),
+			expectedIds: []string{"3", "2", "4"},
+		},
+		{
+			name:        "After removed resource",
+			keys:        []storage.SortKey{{Value: float64(2000)}, {Value: "9"}},
+			expectedIds: []string{"2", "4"},
+		},
+		{
+			name:        "After resource missing field",
+			keys:        storage.SortKeys(sorted[3], fields),
+			expectedIds: []string{},
+		},
+	}
+
+	for _, tt := range testCases {
+		got := storage.SortAfter(sorted, fields, tt.keys)
+
+		gotIds := make([]string, 0, len(got))
+		for _, resource := range got {
+			gotIds = append(gotIds, resource["id"].(string))
+		}
+
+		if !reflect.DeepEqual(gotIds, tt.expectedIds) {
+			t.Fatalf("%s: expected order %v, but got %v", tt.name, tt.expectedIds, gotIds)
+		}
+	}
+}
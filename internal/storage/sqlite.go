@@ -0,0 +1,810 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	// Pure Go SQLite driver, so no CGO is needed.
+	"modernc.org/sqlite"
+)
+
+// sqliteSchema creates the tables of the database, if they don't exist already. Resources are stored as
+// json documents, along with the normalized value of their id field, which is indexed per key.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS collections (
+	name     TEXT PRIMARY KEY,
+	id_field TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS resources (
+	seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+	collection TEXT NOT NULL REFERENCES collections (name) ON DELETE CASCADE,
+	id         TEXT NOT NULL,
+	doc        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS resources_collection_id ON resources (collection, id);
+CREATE TABLE IF NOT EXISTS singulars (
+	name TEXT PRIMARY KEY,
+	doc  TEXT NOT NULL
+);`
+
+var (
+	// registerSQLiteFunctions registers the sql functions of sqlite storage, once for every connection.
+	registerSQLiteFunctions sync.Once
+	// arrayIndexRegex matches dot separated paths of fields, that contain an index of an array element.
+	arrayIndexRegex = regexp.MustCompile(`(^|\.)\d+(\.|$)`)
+	// sqlitePatterns caches the compiled patterns of the sequence function, by expression.
+	sqlitePatterns sync.Map
+)
+
+// SQLiteDatabase holds a connection to an SQLite database, shared by the sqlite storage of every key.
+type SQLiteDatabase struct {
+	db *sql.DB
+}
+
+// NewSQLiteDatabase opens the SQLite database of the file, creating it if it doesn't exist, and returns
+// a new sqlite database instance.
+func NewSQLiteDatabase(filename string) (*SQLiteDatabase, error) {
+	registerSQLiteFunctions.Do(func() {
+		// sequence(pattern, id) returns the sequence of the id, captured by the pattern of a sequential id
+		// generator, or null if the id doesn't match it.
+		sqlite.MustRegisterDeterministicScalarFunction("sequence", 2, sqliteSequence)
+	})
+
+	db, err := sql.Open("sqlite", "file:"+filename+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	// A single connection serializes transactions, so concurrent requests never conflict.
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteDatabase{db: db}, nil
+}
+
+// Keys returns the keys of resources and singular resources of the database, sorted by name.
+func (d *SQLiteDatabase) Keys() ([]string, []string, error) {
+	resourceKeys, err := d.names("SELECT name FROM collections ORDER BY name")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	singularKeys, err := d.names("SELECT name FROM singulars ORDER BY name")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resourceKeys, singularKeys, nil
+}
+
+// Import replaces the contents of the database with the ones of the data file.
+func (d *SQLiteDatabase) Import(filename string) error {
+	data, singular, err := readFile(filename, FileKey(filename))
+	if err != nil {
+		return err
+	}
+
+	return d.transaction(func(tx *sql.Tx) error {
+		for _, stmt := range []string{"DELETE FROM resources", "DELETE FROM collections", "DELETE FROM singulars"} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+
+		for key, resources := range data {
+			// Resources are imported with the default id field, and indexed again if storage uses another one.
+			if _, err := tx.Exec("INSERT INTO collections (name, id_field) VALUES (?, ?)", key, "id"); err != nil {
+				return err
+			}
+
+			for _, resource := range resources {
+				if err := insertSQLiteResource(tx, key, "id", resource); err != nil {
+					return err
+				}
+			}
+		}
+
+		for key, resource := range singular {
+			doc, err := json.Marshal(resource)
+			if err != nil {
+				return err
+			}
+
+			if _, err = tx.Exec("INSERT INTO singulars (name, doc) VALUES (?, ?)", key, string(doc)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close the database.
+func (d *SQLiteDatabase) Close() error {
+	return d.db.Close()
+}
+
+func (d *SQLiteDatabase) names(query string) ([]string, error) {
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// transaction runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+func (d *SQLiteDatabase) transaction(fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SQLite implements the storage interface, and uses an SQLite database as 'database'.
+type SQLite struct {
+	database *SQLiteDatabase
+	key      string
+	options
+}
+
+// NewSQLite returns a new sqlite instance. Resources of the key are indexed again, if they were
+// indexed by another id field.
+func NewSQLite(database *SQLiteDatabase, key string, opts ...Option) (*SQLite, error) {
+	s := &SQLite{database: database, key: key, options: newOptions(opts)}
+
+	// The storage for all resources isn't bound to a key.
+	if key == "" {
+		return s, nil
+	}
+
+	err := database.transaction(func(tx *sql.Tx) error {
+		var idField string
+		err := tx.QueryRow("SELECT id_field FROM collections WHERE name = ?", key).Scan(&idField)
+		if errors.Is(err, sql.ErrNoRows) || idField == s.idField {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resources, err := s.resources(tx)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec("DELETE FROM resources WHERE collection = ?", key); err != nil {
+			return err
+		}
+
+		for _, resource := range resources {
+			if err = insertSQLiteResource(tx, key, s.idField, resource); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("UPDATE collections SET id_field = ? WHERE name = ?", s.idField, key)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// IDField returns the name of the id field of sqlite resources.
+func (s *SQLite) IDField() string {
+	return s.idField
+}
+
+// Find all sqlite resources for the specific key, that match the provided filter.
+func (s *SQLite) Find(filter Filter) ([]Resource, error) {
+	var resources []Resource
+
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		if err := s.checkKeyExists(tx); err != nil {
+			return err
+		}
+
+		where, args := sqliteConditions(filter, s.idField)
+
+		rows, err := tx.Query(
+			"SELECT doc FROM resources WHERE collection = ?"+where+" ORDER BY seq",
+			append([]interface{}{s.key}, args...)...,
+		)
+		if err != nil {
+			return err
+		}
+
+		resources, err = scanSQLiteResources(rows)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Equality conditions only narrow down the resources, which are matched against the whole filter.
+	return filterResources(resources, filter), nil
+}
+
+// FindById a sqlite resource for the specific key.
+func (s *SQLite) FindById(id string) (Resource, error) {
+	var resource Resource
+
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		var err error
+		resource, err = s.findById(tx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// Create a new sqlite resource for the specific key.
+func (s *SQLite) Create(newResource Resource) (Resource, error) {
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		if err := s.checkKeyExists(tx); err != nil {
+			return err
+		}
+
+		_, ok := newResource[s.idField]
+		if !ok {
+			id, err := s.generateID(tx)
+			if err != nil {
+				return err
+			}
+
+			newResource[s.idField] = id
+		} else if _, err := s.findById(tx, FormatID(newResource[s.idField])); err == nil {
+			return ErrResourceAlreadyExists
+		} else if !errors.Is(err, ErrResourceNotFound) {
+			return err
+		}
+
+		return insertSQLiteResource(tx, s.key, s.idField, newResource)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+// Replace an existing sqlite resource for the specific key.
+func (s *SQLite) Replace(id string, replaced Resource) (Resource, error) {
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		// Check if resource with the requested id exists.
+		current, err := s.findById(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Keep the id, along with its original type.
+		replaced[s.idField] = current[s.idField]
+
+		return s.set(tx, id, replaced)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// Update an existing sqlite resource for the specific key.
+func (s *SQLite) Update(id string, updatedReq Resource) (Resource, error) {
+	var updated Resource
+
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		// Check if resource with the requested id exists and retrieve it.
+		var err error
+		updated, err = s.findById(tx, id)
+		if err != nil {
+			return err
+		}
+
+		currentID := updated[s.idField]
+
+		// Apply any changes to current resource.
+		for key, val := range updatedReq {
+			updated[key] = val
+		}
+
+		// Keep the id, along with its original type.
+		updated[s.idField] = currentID
+
+		return s.set(tx, id, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Delete an existing sqlite resource for the specific key.
+func (s *SQLite) Delete(id string) error {
+	return s.database.transaction(func(tx *sql.Tx) error {
+		// Check if resource with the requested id exists.
+		if _, err := s.findById(tx, id); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec("DELETE FROM resources WHERE collection = ? AND id = ?", s.key, id)
+
+		return err
+	})
+}
+
+// DB returns all the sqlite resources.
+func (s *SQLite) DB() (Database, error) {
+	data := make(Database)
+
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT name FROM collections")
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var name string
+			if err = rows.Scan(&name); err != nil {
+				rows.Close()
+				return err
+			}
+
+			data[name] = make([]Resource, 0)
+		}
+
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		rows, err = tx.Query("SELECT collection, doc FROM resources ORDER BY seq")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				name string
+				doc  string
+			)
+			if err = rows.Scan(&name, &doc); err != nil {
+				return err
+			}
+
+			var resource Resource
+			if err = json.Unmarshal([]byte(doc), &resource); err != nil {
+				return err
+			}
+
+			data[name] = append(data[name], resource)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// checkKeyExists in the database.
+func (s *SQLite) checkKeyExists(tx *sql.Tx) error {
+	var name string
+	err := tx.QueryRow("SELECT name FROM collections WHERE name = ?", s.key).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrResourceNotFound
+	}
+
+	return err
+}
+
+// resources returns all the sqlite resources for the specific key, in insertion order.
+func (s *SQLite) resources(tx *sql.Tx) ([]Resource, error) {
+	rows, err := tx.Query("SELECT doc FROM resources WHERE collection = ? ORDER BY seq", s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSQLiteResources(rows)
+}
+
+// generateID generates the id of a new sqlite resource. Sequential id generators are only provided the
+// ids they depend on, i.e. the one of the greatest sequence and any id that isn't a number, while
+// other generators are provided every id, without loading the resources.
+func (s *SQLite) generateID(tx *sql.Tx) (interface{}, error) {
+	path := sqliteJSONPath(s.idField)
+
+	g, ok := s.idGenerator.(*templateGenerator)
+	if !ok {
+		ids, err := s.ids(tx, "SELECT id, json_type(doc, ?) FROM resources WHERE collection = ? ORDER BY seq", path, s.key)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.idGenerator.Generate(ids)
+	}
+
+	ids, err := s.ids(
+		tx,
+		"SELECT id, json_type(doc, ?) FROM resources WHERE collection = ? AND IFNULL(json_type(doc, ?), 'null') NOT IN ('integer', 'real') LIMIT 1",
+		path, s.key, path,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.seqRegex != nil {
+		last, err := s.ids(
+			tx,
+			"SELECT id, json_type(doc, ?) FROM resources WHERE collection = ? ORDER BY sequence(?, id) DESC LIMIT 1",
+			path, s.key, g.seqRegex.String(),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, last...)
+	}
+
+	// Generated ids are checked against every id, since the generator is only provided some of them.
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		id, err := g.Generate(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.findById(tx, FormatID(id))
+		if errors.Is(err, ErrResourceNotFound) {
+			return id, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errFailedGenerateID
+}
+
+// ids returns the ids selected by the query, along with their json type, where numbers are
+// returned as such and the rest of the ids in their normalized string form.
+func (s *SQLite) ids(tx *sql.Tx, query string, args ...interface{}) ([]interface{}, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]interface{}, 0)
+	for rows.Next() {
+		var (
+			id       string
+			jsonType sql.NullString
+		)
+		if err = rows.Scan(&id, &jsonType); err != nil {
+			return nil, err
+		}
+
+		if jsonType.String == "integer" || jsonType.String == "real" {
+			if number, err := strconv.ParseFloat(id, 64); err == nil {
+				ids = append(ids, number)
+				continue
+			}
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// findById a sqlite resource for the specific key, using the index of ids.
+func (s *SQLite) findById(tx *sql.Tx, id string) (Resource, error) {
+	if err := s.checkKeyExists(tx); err != nil {
+		return nil, err
+	}
+
+	var doc string
+	err := tx.QueryRow("SELECT doc FROM resources WHERE collection = ? AND id = ? ORDER BY seq LIMIT 1", s.key, id).Scan(&doc)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resource Resource
+	if err = json.Unmarshal([]byte(doc), &resource); err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// set the document of the resource with the requested id, keeping its position.
+func (s *SQLite) set(tx *sql.Tx, id string, resource Resource) error {
+	doc, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE resources SET doc = ? WHERE collection = ? AND id = ?", string(doc), s.key, id)
+
+	return err
+}
+
+// scanSQLiteResources returns the resources of the documents of rows, closing them.
+func scanSQLiteResources(rows *sql.Rows) ([]Resource, error) {
+	defer rows.Close()
+
+	resources := make([]Resource, 0)
+	for rows.Next() {
+		var doc string
+		if err := rows.Scan(&doc); err != nil {
+			return nil, err
+		}
+
+		var resource Resource
+		if err := json.Unmarshal([]byte(doc), &resource); err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, rows.Err()
+}
+
+// sqliteConditions returns the sql conditions of the equality conditions of the filter, along with their
+// arguments. They select a superset of the matching resources, using the index of ids for the id field,
+// while fields of other types than strings and numbers are left to be matched by the filter.
+func sqliteConditions(filter Filter, idField string) (string, []interface{}) {
+	var (
+		where strings.Builder
+		args  []interface{}
+	)
+
+	for _, condition := range filter {
+		if condition.Operator != "" && condition.Operator != OperatorEq {
+			continue
+		}
+
+		if strings.Contains(condition.Field, `"`) {
+			continue
+		}
+
+		if condition.Field == idField {
+			// Values are also matched as is, in case they only look like composite ids.
+			values := append(idValues(condition.Values), condition.Values...)
+
+			where.WriteString(" AND id IN (" + sqlitePlaceholders(len(values)) + ")")
+			for _, value := range values {
+				args = append(args, value)
+			}
+
+			continue
+		}
+
+		values := make([]interface{}, 0, len(condition.Values))
+		for _, value := range condition.Values {
+			values = append(values, value)
+
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				values = append(values, number)
+			}
+		}
+
+		path := sqliteJSONPath(condition.Field)
+
+		// Resources missing the field never match, unless it's looked up by the index of an array element.
+		jsonType := "json_type(doc, ?)"
+		if arrayIndexRegex.MatchString(condition.Field) {
+			jsonType = "IFNULL(json_type(doc, ?), '')"
+		}
+
+		where.WriteString(" AND (" + jsonType + " NOT IN ('text', 'integer', 'real')")
+		where.WriteString(" OR json_extract(doc, ?) IN (" + sqlitePlaceholders(len(values)) + "))")
+		args = append(append(args, path, path), values...)
+	}
+
+	return where.String(), args
+}
+
+// sqliteJSONPath returns the json path of the dot separated path of a field, e.g. '$."author"."name"'.
+func sqliteJSONPath(field string) string {
+	return `$."` + strings.Join(strings.Split(field, "."), `"."`) + `"`
+}
+
+// sqlitePlaceholders returns n comma separated placeholders of arguments.
+func sqlitePlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sqliteSequence implements the sequence function, which returns the sequence captured by the pattern in
+// the id, or null if the id doesn't match it.
+func sqliteSequence(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	expr, ok := args[0].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	id, ok := args[1].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	pattern, ok := sqlitePatterns.Load(expr)
+	if !ok {
+		compiled, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, _ = sqlitePatterns.LoadOrStore(expr, compiled)
+	}
+
+	match := pattern.(*regexp.Regexp).FindStringSubmatch(id)
+	if match == nil {
+		return nil, nil
+	}
+
+	seq, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	return seq, nil
+}
+
+// insertSQLiteResource inserts the resource for the key, indexed by the value of its id field.
+func insertSQLiteResource(tx *sql.Tx, key, idField string, resource Resource) error {
+	doc, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO resources (collection, id, doc) VALUES (?, ?, ?)",
+		key, FormatID(resource[idField]), string(doc),
+	)
+
+	return err
+}
+
+// SingularSQLite implements the singular storage interface, and uses an SQLite database as 'database'.
+type SingularSQLite struct {
+	database *SQLiteDatabase
+	key      string
+}
+
+// NewSingularSQLite returns a new singular sqlite instance.
+func NewSingularSQLite(database *SQLiteDatabase, key string) (*SingularSQLite, error) {
+	return &SingularSQLite{database: database, key: key}, nil
+}
+
+// Get the singular sqlite resource for the specific key.
+func (s *SingularSQLite) Get() (Resource, error) {
+	var resource Resource
+
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		var err error
+		resource, err = s.get(tx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// Replace the singular sqlite resource for the specific key.
+func (s *SingularSQLite) Replace(replaced Resource) (Resource, error) {
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		if _, err := s.get(tx); err != nil {
+			return err
+		}
+
+		return s.set(tx, replaced)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// Update the singular sqlite resource for the specific key.
+func (s *SingularSQLite) Update(updatedReq Resource) (Resource, error) {
+	var updated Resource
+
+	err := s.database.transaction(func(tx *sql.Tx) error {
+		var err error
+		updated, err = s.get(tx)
+		if err != nil {
+			return err
+		}
+
+		// Apply any changes to current resource.
+		for key, val := range updatedReq {
+			updated[key] = val
+		}
+
+		return s.set(tx, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *SingularSQLite) get(tx *sql.Tx) (Resource, error) {
+	var doc string
+	err := tx.QueryRow("SELECT doc FROM singulars WHERE name = ?", s.key).Scan(&doc)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resource Resource
+	if err = json.Unmarshal([]byte(doc), &resource); err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (s *SingularSQLite) set(tx *sql.Tx, resource Resource) error {
+	doc, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE singulars SET doc = ? WHERE name = ?", string(doc), s.key)
+
+	return err
+}
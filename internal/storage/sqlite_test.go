@@ -0,0 +1,347 @@
+package storage_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+func TestSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db.json")
+	content := `{"posts": [{"id": 1, "title": "json-server"}, {"id": 2, "title": "json-server in go"}], "profile": {"name": "typicode"}}`
+	if err = ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := storage.NewSQLiteDatabase(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	resourceKeys, singularKeys, err := database.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"posts"}; !reflect.DeepEqual(resourceKeys, expected) {
+		t.Fatalf("expected resource keys %v, but got %v", expected, resourceKeys)
+	}
+
+	if expected := []string{"profile"}; !reflect.DeepEqual(singularKeys, expected) {
+		t.Fatalf("expected singular keys %v, but got %v", expected, singularKeys)
+	}
+
+	posts, err := storage.NewSQLite(database, "posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := posts.Create(storage.Resource{"title": "json-server in sqlite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created["id"] != float64(3) {
+		t.Fatalf("expected id %v, but got %v", float64(3), created["id"])
+	}
+
+	if _, err = posts.Create(storage.Resource{"id": "3", "title": "duplicate"}); !errors.Is(err, storage.ErrResourceAlreadyExists) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceAlreadyExists, err)
+	}
+
+	if _, err = posts.Update("1", storage.Resource{"id": "10", "title": "json-server in js"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = posts.Replace("3", storage.Resource{"title": "json-server in sql"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = posts.Delete("2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = posts.Delete("2"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	got, err := posts.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []storage.Resource{
+		{"id": float64(1), "title": "json-server in js"},
+		{"id": float64(3), "title": "json-server in sql"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected data %v, but got %v", expected, got)
+	}
+
+	comments, err := storage.NewSQLite(database, "comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = comments.Find(nil); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	profile, err := storage.NewSingularSQLite(database, "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = profile.Update(storage.Resource{"age": float64(30)}); err != nil {
+		t.Fatal(err)
+	}
+
+	gotProfile, err := profile.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"name": "typicode", "age": float64(30)}); !reflect.DeepEqual(gotProfile, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, gotProfile)
+	}
+
+	db, err := storage.NewSQLite(database, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expectedDB := (storage.Database{"posts": expected}); !reflect.DeepEqual(gotDB, expectedDB) {
+		t.Fatalf("expected data %v, but got %v", expectedDB, gotDB)
+	}
+}
+
+func TestSQLite_IDField(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db.json")
+	if err = ioutil.WriteFile(file, []byte(`{"products": [{"sku": "a1", "id": "other"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := storage.NewSQLiteDatabase(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resources must be indexed again by the id field of storage.
+	products, err := storage.NewSQLite(database, "products", storage.WithIDField("sku"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = products.FindById("other"); !errors.Is(err, storage.ErrResourceNotFound) {
+		t.Fatalf("expected error %v, but got %v", storage.ErrResourceNotFound, err)
+	}
+
+	got, err := products.FindById("a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (storage.Resource{"sku": "a1", "id": "other"}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected resource %v, but got %v", expected, got)
+	}
+}
+
+func TestSQLite_Find(t *testing.T) {
+	content := `{"posts": [
+		{"id": 1, "title": "json-server", "views": 10, "draft": false, "author": {"name": "typicode"}, "tags": ["js"]},
+		{"id": 2, "title": "json-server in go", "views": 20, "draft": true, "author": {"name": "chanioxaris"}, "tags": ["go"]},
+		{"id": "3", "title": "10", "views": 10.5, "author": {"name": "chanioxaris"}}
+	]}`
+
+	testCases := []struct {
+		name     string
+		filter   storage.Filter
+		expected []interface{}
+	}{
+		{
+			name:     "Equal string field",
+			filter:   storage.Filter{{Field: "title", Values: []string{"json-server in go"}}},
+			expected: []interface{}{float64(2)},
+		},
+		{
+			name:     "Equal number field",
+			filter:   storage.Filter{{Field: "views", Values: []string{"10", "10.5"}}},
+			expected: []interface{}{float64(1), "3"},
+		},
+		{
+			name:     "Equal boolean field",
+			filter:   storage.Filter{{Field: "draft", Values: []string{"true"}}},
+			expected: []interface{}{float64(2)},
+		},
+		{
+			name:     "Equal nested field",
+			filter:   storage.Filter{{Field: "author.name", Values: []string{"chanioxaris"}}},
+			expected: []interface{}{float64(2), "3"},
+		},
+		{
+			name:     "Equal array element",
+			filter:   storage.Filter{{Field: "tags.0", Values: []string{"go"}}},
+			expected: []interface{}{float64(2)},
+		},
+		{
+			name:     "Equal id field of any type",
+			filter:   storage.Filter{{Field: "id", Values: []string{"1", "3"}}},
+			expected: []interface{}{float64(1), "3"},
+		},
+		{
+			name: "Equal and other conditions",
+			filter: storage.Filter{
+				{Field: "author.name", Values: []string{"chanioxaris"}},
+				{Field: "views", Operator: storage.OperatorGte, Values: []string{"15"}},
+			},
+			expected: []interface{}{float64(2)},
+		},
+		{
+			name:     "Number value of string field",
+			filter:   storage.Filter{{Field: "title", Values: []string{"10"}}},
+			expected: []interface{}{"3"},
+		},
+		{
+			name:     "Missing field",
+			filter:   storage.Filter{{Field: "category", Values: []string{"go"}}},
+			expected: []interface{}{},
+		},
+	}
+
+	posts := newTestSQLite(t, content, "posts")
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := posts.Find(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]interface{}, 0, len(got))
+			for _, resource := range got {
+				ids = append(ids, resource["id"])
+			}
+
+			if !reflect.DeepEqual(ids, tc.expected) {
+				t.Fatalf("expected ids %v, but got %v", tc.expected, ids)
+			}
+		})
+	}
+}
+
+func TestSQLite_Create(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		strategy string
+		expected interface{}
+	}{
+		{
+			name:     "Auto increment number ids",
+			content:  `{"posts": [{"id": 3}, {"id": 10}, {"id": 2}]}`,
+			strategy: storage.IDGeneratorAutoIncrement,
+			expected: float64(11),
+		},
+		{
+			name:     "Auto increment string ids",
+			content:  `{"posts": [{"id": 1}, {"id": "7"}, {"id": 2}]}`,
+			strategy: storage.IDGeneratorAutoIncrement,
+			expected: "8",
+		},
+		{
+			name:     "Auto increment without resources",
+			content:  `{"posts": []}`,
+			strategy: storage.IDGeneratorAutoIncrement,
+			expected: float64(1),
+		},
+		{
+			name:     "Template sequence ids",
+			content:  `{"posts": [{"id": "usr_9"}, {"id": "usr_12"}, {"id": "other_99"}]}`,
+			strategy: "usr_{seq}",
+			expected: "usr_13",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGenerator, err := storage.NewIDGenerator(tc.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			posts := newTestSQLite(t, tc.content, "posts", storage.WithIDGenerator(idGenerator))
+
+			created, err := posts.Create(storage.Resource{"title": "json-server"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if created["id"] != tc.expected {
+				t.Fatalf("expected id %v, but got %v", tc.expected, created["id"])
+			}
+		})
+	}
+}
+
+// newTestSQLite returns the sqlite storage of the key, of a database imported from the content.
+func newTestSQLite(t *testing.T, content, key string, opts ...storage.Option) *storage.SQLite {
+	dir, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	file := filepath.Join(dir, "db.json")
+	if err = ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := storage.NewSQLiteDatabase(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err = database.Import(file); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := storage.NewSQLite(database, key, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
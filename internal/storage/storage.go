@@ -24,11 +24,19 @@ type Database map[string][]Resource
 
 // Storage interface to handle storage operations.
 type Storage interface {
-	Find() ([]Resource, error)
+	Find(Filter) ([]Resource, error)
 	FindById(string) (Resource, error)
 	Create(Resource) (Resource, error)
 	Replace(string, Resource) (Resource, error)
 	Update(string, Resource) (Resource, error)
 	Delete(string) error
 	DB() (Database, error)
+	IDField() string
+}
+
+// SingularStorage interface to handle storage operations of a singular resource, e.g. a 'profile' object.
+type SingularStorage interface {
+	Get() (Resource, error)
+	Replace(Resource) (Resource, error)
+	Update(Resource) (Resource, error)
 }
@@ -0,0 +1,311 @@
+// Package validation provides linting of data files, reporting every problem of their resources at once.
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+	"github.com/chanioxaris/json-server/internal/storage"
+)
+
+// Problem is a problem of the contents of a data file.
+type Problem struct {
+	File string
+	// Line and Column of the problem in the file, if known, e.g. of syntax errors.
+	Line   int
+	Column int
+	// Path of the problem in the contents of the file, e.g. 'posts[2]'.
+	Path    string
+	Message string
+}
+
+// String returns the problem prefixed by its location, e.g. 'db.json:3:5: message' or
+// 'db.json: posts[2]: message'.
+func (p Problem) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	}
+
+	if p.Path != "" {
+		return fmt.Sprintf("%s: %s: %s", location, p.Path, p.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", location, p.Message)
+}
+
+// Source is the decoded contents of a data file, by resource key.
+type Source struct {
+	File    string
+	Content map[string]interface{}
+}
+
+// Read returns the source of the file, decoded by the format of its extension, along with any problems
+// of its contents. If key is not empty, the file contains only the resources of that key.
+func Read(file, key string) (Source, []Problem) {
+	src := Source{File: file}
+
+	value, err := storage.ReadContent(file, key)
+	if err != nil {
+		return src, []Problem{fileProblem(file, err)}
+	}
+
+	if key != "" {
+		src.Content = map[string]interface{}{key: value}
+		return src, nil
+	}
+
+	content, ok := value.(map[string]interface{})
+	if !ok {
+		return src, []Problem{{File: file, Message: "contents must be an object, with resources by key"}}
+	}
+
+	src.Content = content
+
+	return src, nil
+}
+
+// fileProblem returns the problem of a file that can't be read or decoded. Syntax errors of json files
+// are located by line and column.
+func fileProblem(file string, err error) Problem {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return Problem{File: file, Message: err.Error()}
+	}
+
+	problem := Problem{File: file, Message: "syntax error: " + syntaxErr.Error()}
+
+	contentBytes, readErr := ioutil.ReadFile(file)
+	if readErr != nil {
+		return problem
+	}
+
+	problem.Line, problem.Column = position(contentBytes, syntaxErr.Offset)
+
+	return problem
+}
+
+// position returns the line and column of the character that the error occurred at, which is the
+// last one read before the offset.
+func position(contentBytes []byte, offset int64) (int, int) {
+	idx := int(offset) - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	if idx > len(contentBytes) {
+		idx = len(contentBytes)
+	}
+
+	before := contentBytes[:idx]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := idx - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// Validator reports the problems of resources, e.g. records missing ids or dangling foreign keys.
+type Validator struct {
+	idField   string
+	idFields  map[string]string
+	inflector *inflection.Inflector
+}
+
+// New returns a new validator instance. Resources are identified by the id field, unless overridden
+// per resource key, while the inflector resolves the resources that foreign keys refer to, e.g. 'postId'.
+func New(idField string, idFields map[string]string, inflector *inflection.Inflector) *Validator {
+	return &Validator{idField: idField, idFields: idFields, inflector: inflector}
+}
+
+// IDField returns the name of the id field of the resources of key.
+func (v *Validator) IDField(key string) string {
+	if idField, ok := v.idFields[key]; ok {
+		return idField
+	}
+
+	return v.idField
+}
+
+// collection is an array of resources, with the formatted ids of its records.
+type collection struct {
+	file    string
+	records []interface{}
+	ids     map[string]bool
+}
+
+// Validate returns every problem of the resources of the sources: unsupported resource types, resources
+// defined in multiple files, records that aren't objects, records missing ids, duplicate ids, mixed id
+// types, and foreign keys that refer to missing resources.
+func (v *Validator) Validate(sources []Source) []Problem {
+	problems := make([]Problem, 0)
+	collections := make(map[string]*collection)
+	keys := make([]string, 0)
+	defined := make(map[string]string)
+
+	for _, src := range sources {
+		for _, key := range sortedKeys(src.Content) {
+			if other, ok := defined[key]; ok {
+				problems = append(problems, Problem{File: src.File, Path: key, Message: fmt.Sprintf("resource also defined in %s", other)})
+				continue
+			}
+
+			defined[key] = src.File
+
+			switch value := src.Content[key].(type) {
+			case []interface{}:
+				c, idProblems := v.collection(src.File, key, value)
+				problems = append(problems, idProblems...)
+
+				collections[key] = c
+				keys = append(keys, key)
+			case map[string]interface{}:
+				// Objects are singular resources.
+			default:
+				problems = append(problems, Problem{
+					File:    src.File,
+					Path:    key,
+					Message: fmt.Sprintf("unsupported resource type %s, only arrays and objects are supported", typeName(value)),
+				})
+			}
+		}
+	}
+
+	for _, key := range keys {
+		problems = append(problems, v.foreignKeyProblems(key, collections)...)
+	}
+
+	return problems
+}
+
+// collection returns the collection of the records of key, along with the problems of their ids.
+func (v *Validator) collection(file, key string, records []interface{}) (*collection, []Problem) {
+	problems := make([]Problem, 0)
+	idField := v.IDField(key)
+
+	c := &collection{file: file, records: records, ids: make(map[string]bool)}
+	// first contains the index of the first record of every id.
+	first := make(map[string]int)
+	// types contains the number of ids of every type.
+	types := make(map[string]int)
+
+	for idx, item := range records {
+		path := fmt.Sprintf("%s[%d]", key, idx)
+
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			problems = append(problems, Problem{File: file, Path: path, Message: fmt.Sprintf("record is %s, instead of an object", typeName(item))})
+			continue
+		}
+
+		id, ok := record[idField]
+		if !ok {
+			problems = append(problems, Problem{File: file, Path: path, Message: fmt.Sprintf("missing id field %q", idField)})
+			continue
+		}
+
+		switch id.(type) {
+		case float64, string, []interface{}:
+		default:
+			problems = append(problems, Problem{File: file, Path: path, Message: fmt.Sprintf("id is %s, instead of a number, string or array", typeName(id))})
+			continue
+		}
+
+		types[typeName(id)]++
+
+		formatted := storage.FormatID(id)
+		if firstIdx, ok := first[formatted]; ok {
+			problems = append(problems, Problem{File: file, Path: path, Message: fmt.Sprintf("duplicate id %s, also of %s[%d]", formatted, key, firstIdx)})
+			continue
+		}
+
+		first[formatted] = idx
+		c.ids[formatted] = true
+	}
+
+	if len(types) > 1 {
+		counts := make([]string, 0, len(types))
+		for name, count := range types {
+			counts = append(counts, fmt.Sprintf("%s (%d)", name, count))
+		}
+
+		sort.Strings(counts)
+
+		problems = append(problems, Problem{File: file, Path: key, Message: fmt.Sprintf("mixed id types: %s", strings.Join(counts, ", "))})
+	}
+
+	return c, problems
+}
+
+// foreignKeyProblems returns the problems of the foreign keys of the records of key, that refer to
+// missing resources, e.g. 'postId' of a comment without a post.
+func (v *Validator) foreignKeyProblems(key string, collections map[string]*collection) []Problem {
+	problems := make([]Problem, 0)
+	c := collections[key]
+	idField := v.IDField(key)
+
+	for idx, item := range c.records {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, field := range sortedKeys(record) {
+			if field == idField || len(field) <= len("Id") || !strings.HasSuffix(field, "Id") {
+				continue
+			}
+
+			parentKey := v.inflector.Plural(strings.TrimSuffix(field, "Id"))
+			parent, ok := collections[parentKey]
+			if !ok {
+				continue
+			}
+
+			if value := record[field]; value != nil && !parent.ids[storage.FormatID(value)] {
+				problems = append(problems, Problem{
+					File:    c.file,
+					Path:    fmt.Sprintf("%s[%d].%s", key, idx, field),
+					Message: fmt.Sprintf("dangling foreign key, no %s with id %s", parentKey, storage.FormatID(value)),
+				})
+			}
+		}
+	}
+
+	return problems
+}
+
+// typeName returns the json type name of a value, e.g. 'number'.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,162 @@
+package validation_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chanioxaris/json-server/internal/inflection"
+	"github.com/chanioxaris/json-server/internal/validation"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		idFields map[string]string
+		sources  []validation.Source
+		expected []string
+	}{
+		{
+			name: "Valid resources",
+			sources: []validation.Source{{File: "db.json", Content: map[string]interface{}{
+				"posts":    []interface{}{map[string]interface{}{"id": float64(1)}, map[string]interface{}{"id": float64(2)}},
+				"comments": []interface{}{map[string]interface{}{"id": float64(1), "postId": float64(2), "userId": float64(7)}},
+				"profile":  map[string]interface{}{"name": "typicode"},
+			}}},
+			expected: []string{},
+		},
+		{
+			name: "Unsupported resource type",
+			sources: []validation.Source{{File: "db.json", Content: map[string]interface{}{
+				"count": float64(3),
+			}}},
+			expected: []string{"db.json: count: unsupported resource type number, only arrays and objects are supported"},
+		},
+		{
+			name: "Invalid records",
+			sources: []validation.Source{{File: "db.json", Content: map[string]interface{}{
+				"posts": []interface{}{
+					map[string]interface{}{"id": float64(1)},
+					map[string]interface{}{"title": "no id"},
+					"not an object",
+					map[string]interface{}{"id": true},
+					map[string]interface{}{"id": float64(1)},
+				},
+			}}},
+			expected: []string{
+				`db.json: posts[1]: missing id field "id"`,
+				"db.json: posts[2]: record is string, instead of an object",
+				"db.json: posts[3]: id is boolean, instead of a number, string or array",
+				"db.json: posts[4]: duplicate id 1, also of posts[0]",
+			},
+		},
+		{
+			name: "Mixed id types",
+			sources: []validation.Source{{File: "db.json", Content: map[string]interface{}{
+				"posts": []interface{}{
+					map[string]interface{}{"id": "1"},
+					map[string]interface{}{"id": float64(2)},
+					map[string]interface{}{"id": float64(3)},
+				},
+			}}},
+			expected: []string{"db.json: posts: mixed id types: number (2), string (1)"},
+		},
+		{
+			name:     "Id field per resource key",
+			idFields: map[string]string{"products": "sku"},
+			sources: []validation.Source{{File: "db.json", Content: map[string]interface{}{
+				"products": []interface{}{map[string]interface{}{"sku": "a1"}, map[string]interface{}{"id": float64(1)}},
+			}}},
+			expected: []string{`db.json: products[1]: missing id field "sku"`},
+		},
+		{
+			name: "Dangling foreign keys",
+			sources: []validation.Source{{File: "db.json", Content: map[string]interface{}{
+				"people":   []interface{}{map[string]interface{}{"id": float64(1)}},
+				"comments": []interface{}{map[string]interface{}{"id": float64(1), "personId": float64(2)}},
+			}}},
+			expected: []string{"db.json: comments[0].personId: dangling foreign key, no people with id 2"},
+		},
+		{
+			name: "Resource in multiple files",
+			sources: []validation.Source{
+				{File: "db.json", Content: map[string]interface{}{"posts": []interface{}{}}},
+				{File: "posts.json", Content: map[string]interface{}{"posts": []interface{}{}}},
+			},
+			expected: []string{"posts.json: posts: resource also defined in db.json"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := validation.New("id", tc.idFields, inflection.New(nil))
+
+			got := make([]string, 0)
+			for _, problem := range validator.Validate(tc.sources) {
+				got = append(got, problem.String())
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected problems %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		content  string
+		expected []validation.Problem
+	}{
+		{
+			name:     "Valid file",
+			filename: "db.json",
+			content:  `{"posts": []}`,
+			expected: nil,
+		},
+		{
+			name:     "Syntax error",
+			filename: "db.json",
+			content:  "{\n  \"posts\": [\n    {\"id\": 1,}\n  ]\n}",
+			expected: []validation.Problem{{
+				Line:    3,
+				Column:  14,
+				Message: "syntax error: invalid character '}' looking for beginning of object key string",
+			}},
+		},
+		{
+			name:     "Not an object",
+			filename: "db.json",
+			content:  `[]`,
+			expected: []validation.Problem{{Message: "contents must be an object, with resources by key"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(".", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			filename := filepath.Join(dir, tc.filename)
+			if err = ioutil.WriteFile(filename, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			for idx := range tc.expected {
+				tc.expected[idx].File = filename
+			}
+
+			_, got := validation.Read(filename, "")
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected problems %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type errorResponse struct {
@@ -12,21 +14,36 @@ type errorResponse struct {
 
 // Success response on http request. Contains a json body with the provided data.
 func Success(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.WriteHeader(statusCode)
+	SuccessWithHeaders(w, statusCode, data, nil)
+}
 
-	if data != nil {
-		dataBytes, err := json.Marshal(data)
-		if err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
+// SuccessWithHeaders response on http request. Attaches the provided headers to a response,
+// which contains a json body with the provided data.
+func SuccessWithHeaders(w http.ResponseWriter, statusCode int, data interface{}, headers http.Header) {
+	// Headers must be set before writing the status code, otherwise they are ignored.
+	for key, values := range headers {
+		for _, value := range values {
+			w.Header().Add(key, value)
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		_, err = w.Write(dataBytes)
-		if err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
+	if data == nil {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if _, err = w.Write(dataBytes); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
 	}
 }
 
@@ -47,3 +64,18 @@ func Error(w http.ResponseWriter, statusCode int, error string) {
 		return
 	}
 }
+
+// QueryList returns the non empty values of a query parameter, which can be either repeated
+// or contain a comma separated list, e.g. '?_fields=id,title&_fields=author'.
+func QueryList(query url.Values, param string) []string {
+	values := make([]string, 0)
+	for _, value := range query[param] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return values
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -68,6 +69,78 @@ func TestSuccess(t *testing.T) {
 	}
 }
 
+func TestSuccessWithHeaders(t *testing.T) {
+	type body struct {
+		ID    int    `json:"id"`
+		Field string `json:"field"`
+	}
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		data       interface{}
+		headers    http.Header
+	}{
+		{
+			name:       "Response success with headers without data",
+			statusCode: http.StatusOK,
+			data:       nil,
+			headers:    http.Header{"X-Total-Count": []string{"0"}},
+		},
+		{
+			name:       "Response success with headers with data",
+			statusCode: http.StatusOK,
+			data: []body{
+				{
+					ID:    1,
+					Field: "testing success response",
+				},
+			},
+			headers: http.Header{
+				"X-Total-Count": []string{"1"},
+				"Link":          []string{`<http://localhost/resource?_page=1>; rel="first"`},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			web.SuccessWithHeaders(w, tt.statusCode, tt.data, tt.headers)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/success", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		resp := w.Result()
+
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf("expected status code %v, but got %v", tt.statusCode, resp.StatusCode)
+		}
+
+		for key := range tt.headers {
+			if header := resp.Header.Get(key); header != tt.headers.Get(key) {
+				t.Fatalf("expected header %s %v, but got %v", key, tt.headers.Get(key), header)
+			}
+		}
+
+		if tt.data != nil {
+			if header := resp.Header.Get("Content-Type"); header != "application/json" {
+				t.Fatalf("expected header Content-Type %v, but got %v", "application/json", header)
+			}
+
+			var respBody []body
+			if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(respBody, tt.data) {
+				t.Fatalf("expected body %v, but got %v", tt.data, respBody)
+			}
+		}
+	}
+}
+
 func TestError(t *testing.T) {
 	type body struct {
 		Error string `json:"error"`
@@ -110,3 +183,38 @@ func TestError(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryList(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "Missing parameter",
+			query:    "field=value",
+			expected: []string{},
+		},
+		{
+			name:     "Comma separated parameter",
+			query:    "param=id,title",
+			expected: []string{"id", "title"},
+		},
+		{
+			name:     "Repeated parameter with empty values",
+			query:    "param=id,&param=&param=%20title",
+			expected: []string{"id", "title"},
+		},
+	}
+
+	for _, tt := range testCases {
+		query, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := web.QueryList(query, "param"); !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("%s: expected values %v, but got %v", tt.name, tt.expected, got)
+		}
+	}
+}